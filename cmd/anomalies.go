@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var anomaliesCmd = &cobra.Command{
+	Use:     "anomalies",
+	Aliases: []string{"anom"},
+	Short:   "list devices, switch ports, and clients reporting anomalies",
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		anomalies, err := ses.GetAnomalies()
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, anomalies, func() {
+			for _, anomaly := range anomalies {
+				cmd.Printf("%s\n", anomaly.String())
+			}
+		})
+	},
+}
+
+func init() { // nolint: gochecknoinits
+	rootCmd.AddCommand(anomaliesCmd)
+}
@@ -12,16 +12,24 @@ var blockCmd = &cobra.Command{
 		ses, err := initSession(cmd)
 		cobra.CheckErr(err)
 
-		macs, err := ses.GetMACsBy(args...)
+		ids, err := targetIDs(cmd, args)
 		cobra.CheckErr(err)
 
-		_, err = ses.Block(macs...)
+		targets, err := confirmTargets(cmd, ses, "block", ids)
 		cobra.CheckErr(err)
 
-		cmd.Printf("ok\n")
+		if targets == nil {
+			return
+		}
+
+		applyTargets(cmd, ses, targets, ses.Block)
 	},
 }
 
 func init() { // nolint: gochecknoinits
 	clientCmd.AddCommand(blockCmd)
+
+	addConfirmFlags(blockCmd)
+	addStdinFlag(blockCmd)
+	addSkipFlag(blockCmd)
 }
@@ -5,23 +5,33 @@ import (
 )
 
 var forgetCmd = &cobra.Command{
-	Use:     "forget",
+	Use:     "forget <name-or-mac>...",
 	Aliases: []string{"f", "del"},
 	Short:   "forget client",
+	Long: `Permanently remove clients, matched by MAC, name, hostname, or IP, from the
+controller, along with their name, note, fixed IP, and history. This can't
+be undone. The matches and what would be lost are shown first, and the
+word "forget" must be typed to confirm; without a terminal --yes is
+required. Use --dry-run to only show them.`,
+	Example: "client forget old-laptop",
+	Args:    cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		ses, err := initSession(cmd)
 		cobra.CheckErr(err)
 
-		macs, err := ses.GetMACsBy(args...)
+		targets, err := confirmTargets(cmd, ses, "forget", args)
 		cobra.CheckErr(err)
 
-		_, err = ses.Forget(macs...)
-		cobra.CheckErr(err)
+		if targets == nil {
+			return
+		}
 
-		cmd.Printf("ok\n")
+		applyTargets(cmd, ses, targets, ses.Forget)
 	},
 }
 
 func init() { // nolint: gochecknoinits
 	clientCmd.AddCommand(forgetCmd)
+
+	addConfirmFlags(forgetCmd)
 }
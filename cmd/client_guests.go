@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var clientGuestsCmd = &cobra.Command{
+	Use:     "guests",
+	Aliases: []string{"guest", "g"},
+	Short:   "list guest authorizations",
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		guests, err := ses.GetGuests()
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, guests, func() {
+			for _, guest := range guests {
+				cmd.Printf("%s\n", guest.Display(displayOptions))
+			}
+		})
+	},
+}
+
+func init() { // nolint: gochecknoinits
+	clientCmd.AddCommand(clientGuestsCmd)
+}
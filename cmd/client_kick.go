@@ -5,23 +5,32 @@ import (
 )
 
 var kickCmd = &cobra.Command{
-	Use:     "kick",
+	Use:     "kick <name-or-mac>...",
 	Aliases: []string{"k"},
 	Short:   "kick client",
+	Long: `Disconnect clients, matched by MAC, name, hostname, or IP, so they reconnect
+(and may roam to another access point). Unlike block, they are free to
+reconnect straight away. From a terminal the matches are shown for
+confirmation first; pass --yes to skip it or --dry-run to only show them.`,
+	Example: "client kick kids-tablet",
+	Args:    cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		ses, err := initSession(cmd)
 		cobra.CheckErr(err)
 
-		macs, err := ses.GetMACsBy(args...)
+		targets, err := confirmTargets(cmd, ses, "kick", args)
 		cobra.CheckErr(err)
 
-		_, err = ses.Kick(macs...)
-		cobra.CheckErr(err)
+		if targets == nil {
+			return
+		}
 
-		cmd.Printf("ok\n")
+		applyTargets(cmd, ses, targets, ses.Kick)
 	},
 }
 
 func init() { // nolint: gochecknoinits
 	clientCmd.AddCommand(kickCmd)
+
+	addConfirmFlags(kickCmd)
 }
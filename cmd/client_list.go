@@ -1,12 +1,21 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
 	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
 )
 
-var allClients bool
+var (
+	allClients    bool
+	clientFilters []string
+	clientGroups  []string
+	clientGrouped bool
+	newWithin     time.Duration
+)
 
 var clientListCmd = &cobra.Command{
 	Use:     "list",
@@ -21,10 +30,40 @@ var clientListCmd = &cobra.Command{
 			fetch = ses.GetAllClients
 		}
 
-		clients, err := fetch()
+		filters, err := parseClientFilters(clientFilters)
+		cobra.CheckErr(err)
+
+		if newWithin > 0 {
+			filters = append(filters, unifi.NewClient(newWithin))
+		}
+
+		groups, err := groupsFromConfig()
+		cobra.CheckErr(err)
+
+		inGroups, err := groupFilters(groups, clientGroups)
 		cobra.CheckErr(err)
 
-		display.ClientsTable(cmd.OutOrStdout(), clients).Render()
+		clients, err := fetch(append(filters, inGroups...)...)
+		cobra.CheckErr(err)
+
+		if clientGrouped {
+			grouped := byGroup(groups, clients, groups.ClientGroup)
+			writeOutput(cmd, grouped, func() {
+				display.ClientsByGroup(cmd.OutOrStdout(), displayOptions, groups, clients, outputFormat == display.FormatWide)
+			})
+
+			return
+		}
+
+		writeOutput(cmd, clients, func() {
+			if outputFormat == display.FormatWide {
+				display.WideClientsTable(cmd.OutOrStdout(), displayOptions, clients).Render()
+
+				return
+			}
+
+			display.ClientsTable(cmd.OutOrStdout(), displayOptions, clients).Render()
+		})
 	},
 }
 
@@ -32,4 +71,11 @@ func init() { // nolint: gochecknoinits
 	clientCmd.AddCommand(clientListCmd)
 
 	clientListCmd.Flags().BoolVar(&allClients, "all", allClients, "show all clients")
+	clientListCmd.Flags().StringArrayVar(&clientFilters, "filter", clientFilters,
+		`filter clients, e.g. "in 192.168.10.0/24", "wired", "not guest" (repeatable)`)
+	clientListCmd.Flags().StringSliceVar(&clientGroups, "group", clientGroups,
+		"only show clients in these groups (from the groups config)")
+	clientListCmd.Flags().BoolVar(&clientGrouped, "grouped", clientGrouped, "section the output by group")
+	clientListCmd.Flags().DurationVar(&newWithin, "new-within", newWithin,
+		`only show clients first seen within this long, e.g. 1h (with --all, includes disconnected ones)`)
 }
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+// RenameResult reports the outcome of one row of a rename file.
+type RenameResult struct {
+	Line    int    `json:"line"`
+	MAC     string `json:"mac"`
+	Name    string `json:"name"`
+	FixedIP string `json:"fixed_ip,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+var renameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "set client names and fixed IPs from a CSV file",
+	Long: `Set client names and fixed IPs from a CSV file of mac,name,fixed_ip rows.
+
+The fixed_ip column may be empty or omitted, which leaves any fixed IP
+assignment as it is; only the name, and the fixed IP when given, are
+changed on the client's record. A header row starting with
+"mac" is skipped. Rows with an invalid MAC, or a MAC the controller does
+not know, are reported and skipped.`,
+	Example: "client rename --file names.csv",
+	Run: func(cmd *cobra.Command, args []string) {
+		var in io.Reader = cmd.InOrStdin()
+
+		if renameFile != "-" {
+			f, err := os.Open(renameFile)
+			cobra.CheckErr(err)
+
+			defer f.Close()
+
+			in = f
+		}
+
+		rows, err := readRenameCSV(in)
+		cobra.CheckErr(err)
+
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		clients, err := ses.GetAllClients()
+		cobra.CheckErr(err)
+
+		known := map[string]bool{}
+		for _, c := range clients {
+			if mac, err := net.ParseMAC(c.MAC.String()); err == nil {
+				known[mac.String()] = true
+			}
+		}
+
+		failed := 0
+
+		for ix := range rows {
+			row := &rows[ix]
+
+			switch {
+			case row.Status != "":
+			case !known[row.MAC]:
+				row.Status, row.Error = "skipped", "unknown to the controller"
+			default:
+				if _, err := ses.RenameClient(unifi.MAC(row.MAC), row.Name, row.FixedIP); err != nil {
+					row.Status, row.Error = "failed", err.Error()
+				} else {
+					row.Status = "ok"
+				}
+			}
+
+			if row.Status != "ok" {
+				failed++
+			}
+		}
+
+		writeOutput(cmd, rows, func() {
+			t := table.NewWriter()
+			t.SetStyle(display.StyleDefault)
+			t.SetOutputMirror(cmd.OutOrStdout())
+			t.AppendHeader(table.Row{"Line", "MAC", "Name", "Fixed IP", "Status", "Error"})
+
+			for _, row := range rows {
+				t.AppendRow(table.Row{row.Line, row.MAC, row.Name, row.FixedIP, row.Status, row.Error})
+			}
+
+			t.AppendFooter(table.Row{"", "", "", "", fmt.Sprintf("%d failed", failed)})
+			t.Render()
+		})
+
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// readRenameCSV parses mac,name,fixed_ip rows. Rows that fail validation
+// are returned with their status already set.
+func readRenameCSV(in io.Reader) ([]RenameResult, error) {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	var rows []RenameResult
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("reading csv: %w", err)
+		}
+
+		line, _ := r.FieldPos(0)
+
+		if len(rows) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "mac") {
+			continue
+		}
+
+		row := RenameResult{Line: line, MAC: strings.TrimSpace(record[0])}
+
+		if len(record) > 1 {
+			row.Name = strings.TrimSpace(record[1])
+		}
+
+		if len(record) > 2 {
+			row.FixedIP = strings.TrimSpace(record[2])
+		}
+
+		mac, err := net.ParseMAC(row.MAC)
+
+		switch {
+		case err != nil:
+			row.Status, row.Error = "skipped", "invalid MAC"
+		case row.FixedIP != "" && net.ParseIP(row.FixedIP) == nil:
+			row.Status, row.Error = "skipped", "invalid fixed IP"
+		case row.Name == "":
+			row.Status, row.Error = "skipped", "missing name"
+		}
+
+		if err == nil {
+			// The controller reports MACs lower case and colon separated.
+			row.MAC = mac.String()
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+var renameFile = "-"
+
+func init() { // nolint: gochecknoinits
+	clientCmd.AddCommand(renameCmd)
+
+	renameCmd.Flags().StringVar(&renameFile, "file", renameFile, "CSV file of mac,name,fixed_ip rows (- for stdin)")
+}
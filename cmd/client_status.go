@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+// ClientStatus is the detailed view of one client shown by client status.
+type ClientStatus struct {
+	Query    string `json:"query"`
+	Name     string `json:"name,omitempty"`
+	MAC      string `json:"mac,omitempty"`
+	IP       string `json:"ip,omitempty"`
+	State    string `json:"state"`
+	Via      string `json:"via,omitempty"`
+	Upstream string `json:"upstream,omitempty"`
+	ESSID    string `json:"essid,omitempty"`
+	Signal   int64  `json:"signal,omitempty"`
+	Uptime   int64  `json:"uptime,omitempty"`
+	Offline  int64  `json:"offline_seconds,omitempty"`
+	RxBytes  int64  `json:"rx_bytes,omitempty"`
+	TxBytes  int64  `json:"tx_bytes,omitempty"`
+	Blocked  bool   `json:"blocked"`
+	Guest    bool   `json:"guest"`
+	Verdict  string `json:"verdict"`
+
+	client *unifi.Client
+}
+
+var clientStatusCmd = &cobra.Command{
+	Use:   "status <name-or-mac>...",
+	Short: "show a detailed status for specific clients",
+	Long: `Show a detailed status for specific clients, matched by MAC, name, or hostname.
+
+Connected clients are shown with their live details; clients that are known
+but not connected are shown from their stored record. The verdict is "ok",
+or the most pressing problem found. Exits non-zero if any client is not found.`,
+	Example: "client status nas cam-front cam-back",
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		active, err := ses.GetClients()
+		cobra.CheckErr(err)
+
+		known, err := ses.GetAllClients()
+		cobra.CheckErr(err)
+
+		missing := 0
+		statuses := make([]ClientStatus, 0, len(args))
+
+		for _, query := range args {
+			client := findClient(query, active)
+			if client == nil {
+				client = findClient(query, known)
+			}
+
+			if client == nil {
+				missing++
+
+				statuses = append(statuses, ClientStatus{Query: query, State: "unknown", Verdict: "not found"})
+
+				continue
+			}
+
+			statuses = append(statuses, newClientStatus(query, client))
+		}
+
+		writeOutput(cmd, statuses, func() {
+			for ix := range statuses {
+				clientStatusTable(cmd, &statuses[ix])
+			}
+		})
+
+		if missing > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// findClient returns the first client whose MAC, name, or hostname matches
+// query, ignoring case.
+func findClient(query string, clients []unifi.Client) *unifi.Client {
+	for ix := range clients {
+		c := &clients[ix]
+		for _, candidate := range []string{c.MAC.String(), c.Name, c.Hostname, c.DisplayName()} {
+			if candidate != "" && strings.EqualFold(candidate, query) {
+				return c
+			}
+		}
+	}
+
+	return nil
+}
+
+func newClientStatus(query string, client *unifi.Client) ClientStatus {
+	status := ClientStatus{
+		Query:    query,
+		Name:     client.DisplayName(),
+		MAC:      client.MAC.String(),
+		IP:       client.DisplayIP(displayOptions),
+		State:    client.ConnectionState().String(),
+		Upstream: client.DisplaySwitchName(),
+		Uptime:   client.Uptime,
+		RxBytes:  client.BytesReceived,
+		TxBytes:  client.BytesSent,
+		Blocked:  client.IsBlocked,
+		Guest:    client.IsGuest,
+		Verdict:  client.Verdict(displayOptions),
+		client:   client,
+	}
+
+	if client.IsActive() {
+		status.Via = client.ConnectedVia()
+	}
+
+	if d, ok := client.OfflineDuration(); ok {
+		status.Offline = int64(d / time.Second)
+	}
+
+	if client.IsWired {
+		status.RxBytes, status.TxBytes = client.WiredBytesReceived, client.WiredBytesSent
+	} else {
+		status.ESSID, status.Signal = client.ESSID, client.Signal
+	}
+
+	return status
+}
+
+func clientStatusTable(cmd *cobra.Command, status *ClientStatus) {
+	t := table.NewWriter()
+	t.SetStyle(display.StyleDefault)
+	t.SetOutputMirror(cmd.OutOrStdout())
+	t.SetTitle(status.Query)
+
+	if status.client == nil {
+		t.AppendRow(table.Row{"Verdict", status.Verdict})
+		t.Render()
+
+		return
+	}
+
+	c := status.client
+
+	t.AppendRows([]table.Row{
+		{"Name", status.Name},
+		{"MAC", status.MAC},
+		{"IP", status.IP},
+		{"State", status.State},
+		{"Via", strings.TrimSpace(status.Via + " " + status.Upstream)},
+	})
+
+	if !c.IsWired {
+		t.AppendRow(table.Row{"Wireless", fmt.Sprintf("%s, %d dBm", status.ESSID, status.Signal)})
+	}
+
+	// Connected for is the current session; offline for is the time since
+	// the client was last seen.
+	if _, ok := c.SessionDuration(); ok {
+		t.AppendRow(table.Row{"Connected for", c.DisplaySessionDuration()})
+	} else if _, ok := c.OfflineDuration(); ok {
+		t.AppendRow(table.Row{"Offline for", c.DisplayOfflineDuration()})
+	}
+
+	t.AppendRows([]table.Row{
+		{"Traffic", fmt.Sprintf("%s↓ %s↑", c.DisplayReceivedBytes(), c.DisplaySentBytes())},
+		{"Blocked", status.Blocked},
+		{"Guest", status.Guest},
+		{"Verdict", status.Verdict},
+	})
+
+	t.Render()
+}
+
+func init() { // nolint: gochecknoinits
+	clientCmd.AddCommand(clientStatusCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+var clientTraceCmd = &cobra.Command{
+	Use:   "trace <name-or-mac>",
+	Short: "show the path from a connected client up to the gateway",
+	Long: `Show each hop from a connected client through its access point and
+switches up to the gateway, with the port each hop is connected to on the
+next device, its link speed and error counters, and each hop's satisfaction.`,
+	Example: "client trace kids-tablet",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		active, err := ses.GetClients()
+		cobra.CheckErr(err)
+
+		client := findClient(args[0], active)
+		if client == nil {
+			cobra.CheckErr(fmt.Errorf("no connected client %q", args[0]))
+		}
+
+		hops, err := ses.TraceClient(client.MAC)
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, hops, func() {
+			t := table.NewWriter()
+			t.SetStyle(display.StyleDefault)
+			t.SetOutputMirror(cmd.OutOrStdout())
+			t.AppendHeader(table.Row{"Name", "Type", "MAC", "Port", "Link", "Signal", "Satisfaction", "Errors", "Dropped"})
+
+			for _, hop := range hops {
+				port, signal := "", ""
+
+				if hop.Port > 0 {
+					port = fmt.Sprintf("%d %s", hop.Port, hop.PortName)
+				}
+
+				if hop.Signal != 0 {
+					signal = fmt.Sprintf("%d dBm", hop.Signal)
+				}
+
+				t.AppendRow(table.Row{
+					hop.Name, hop.Type, hop.MAC, port, hop.Link, signal,
+					hop.Satisfaction, hop.PortErrors, hop.PortDropped,
+				})
+			}
+
+			t.Render()
+		})
+	},
+}
+
+func init() { // nolint: gochecknoinits
+	clientCmd.AddCommand(clientTraceCmd)
+}
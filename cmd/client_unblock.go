@@ -12,16 +12,24 @@ var unblockCmd = &cobra.Command{
 		ses, err := initSession(cmd)
 		cobra.CheckErr(err)
 
-		macs, err := ses.GetMACsBy(args...)
+		ids, err := targetIDs(cmd, args)
 		cobra.CheckErr(err)
 
-		_, err = ses.Unblock(macs...)
+		targets, err := confirmTargets(cmd, ses, "unblock", ids)
 		cobra.CheckErr(err)
 
-		cmd.Printf("ok\n")
+		if targets == nil {
+			return
+		}
+
+		applyTargets(cmd, ses, targets, ses.Unblock)
 	},
 }
 
 func init() { // nolint: gochecknoinits
 	clientCmd.AddCommand(unblockCmd)
+
+	addConfirmFlags(unblockCmd)
+	addStdinFlag(unblockCmd)
+	addSkipFlag(unblockCmd)
 }
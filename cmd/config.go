@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var configCmd = &cobra.Command{
+	Use:     "config",
+	Aliases: []string{"cfg"},
+	Short:   "configuration tools",
+	// The config commands don't talk to the controller, so the
+	// credentials are not required.
+	PersistentPreRun: credentialsOptional,
+}
+
+// credentialsOptional lifts the required marks from the credential flags,
+// for commands that don't log in.
+func credentialsOptional(cmd *cobra.Command, _ []string) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if _, ok := f.Annotations[cobra.BashCompOneRequiredFlag]; ok {
+			f.Annotations[cobra.BashCompOneRequiredFlag] = []string{"false"}
+		}
+	})
+}
+
+func init() { // nolint: gochecknoinits
+	rootCmd.AddCommand(configCmd)
+}
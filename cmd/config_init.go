@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "write a commented config file template",
+	Long: `Write a commented config file listing the supported keys and their defaults.
+
+The endpoint and credentials are taken from the flags when given, otherwise
+they are prompted for when running in a terminal. The file is written to
+--config, or ~/.unifi-scheduler.yaml, and is not overwritten without --force.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cfgFile
+		if path == "" {
+			home, err := os.UserHomeDir()
+			cobra.CheckErr(err)
+
+			path = filepath.Join(home, ".unifi-scheduler.yaml")
+		}
+
+		if _, err := os.Stat(path); err == nil && !configInitForce {
+			cobra.CheckErr(fmt.Errorf("%s already exists; use --force to overwrite", path))
+		}
+
+		values := configTemplateValues{
+			Endpoint:        endpoint,
+			Username:        username,
+			Password:        password,
+			NATSURL:         natsURL,
+			NATSCreds:       natsCreds,
+			NATSConnTimeout: natsConnTimeout.String(),
+			NATSOpTimeout:   natsOpTimeout.String(),
+			StreamReplicas:  streamReplicas,
+			KVReplicas:      kvReplicas,
+		}
+
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			in := bufio.NewReader(cmd.InOrStdin())
+			out := cmd.ErrOrStderr()
+
+			values.Endpoint = prompt(in, out, "UniFi endpoint (e.g. https://192.168.1.1)", values.Endpoint)
+			values.Username = prompt(in, out, "Username", values.Username)
+
+			if values.Password == "" {
+				fmt.Fprint(out, "Password: ")
+
+				pw, err := term.ReadPassword(int(os.Stdin.Fd()))
+				fmt.Fprintln(out)
+				cobra.CheckErr(err)
+
+				values.Password = string(pw)
+			}
+		}
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		cobra.CheckErr(err)
+
+		defer f.Close()
+
+		cobra.CheckErr(configTemplate.Execute(f, values))
+
+		infof(cmd, "wrote %s\n", path)
+	},
+}
+
+type configTemplateValues struct {
+	Endpoint        string
+	Username        string
+	Password        string
+	NATSURL         string
+	NATSCreds       string
+	NATSConnTimeout string
+	NATSOpTimeout   string
+	StreamReplicas  int
+	KVReplicas      int
+}
+
+var configTemplate = template.Must(template.New("config").Parse(`# unifi-scheduler configuration.
+#
+# Every key can also be given as a flag, or as an UNIFI_ environment variable
+# (e.g. UNIFI_NATS_OP_TIMEOUT). Flags take precedence over the environment,
+# which takes precedence over this file. Run "unifi-scheduler config show"
+# to see the effective values.
+
+# UniFi controller.
+endpoint: {{ printf "%q" .Endpoint }}
+username: {{ printf "%q" .Username }}
+password: {{ printf "%q" .Password }}
+
+# Profiles override the settings above for one controller when selected
+# with --profile (or a top level "profile: home" default).
+# profiles:
+#   office:
+#     endpoint: https://10.0.0.1
+#     username: admin
+#     password: ""
+
+# Retry the initial login, e.g. while the controller reboots; -1 retries
+# forever, with the wait doubling up to a minute.
+# startup-retries: 0
+# startup-retry-wait: 2s
+
+# Output defaults: table, json, or yaml; si or iec sizes; bits or bytes rates;
+# the time zone for displayed times, and absolute instead of relative times.
+# output: table
+# units: si
+# rate-units: bits
+# timezone: America/Los_Angeles
+# absolute-time: false
+# time-format: 2006-01-02T15:04:05Z07:00
+
+# NATS, used by the "nats" commands and to mirror logs.
+nats_url: {{ printf "%q" .NATSURL }}
+# nats_creds: {{ printf "%q" .NATSCreds }}
+# nats-conn-timeout: {{ .NATSConnTimeout }}
+# nats-op-timeout: {{ .NATSOpTimeout }}
+# stream-replicas: {{ .StreamReplicas }}
+# kv-replicas: {{ .KVReplicas }}
+
+# Notifications from the NATS agent for events at or above severity
+# (info, warning, or critical).
+# notify:
+#   severity: warning
+#   webhook:
+#     url: https://example.com/hook
+#   slack:
+#     webhook_url: https://hooks.slack.com/services/...
+#     channel: "#network"
+#   email:
+#     addr: smtp.example.com:587
+#     username: ""
+#     password: ""
+#     from: unifi@example.com
+#     to: [me@example.com]
+
+# Presence rules, notified when matching clients connect. Times are local
+# and the window may wrap midnight; unknown notifies on never seen clients.
+# presence:
+#   unknown: false
+#   allow: ["guest-*"]
+#   rules:
+#     - name: curfew
+#       match: ["kids-phone", "aa:bb:cc:*"]
+#       from: "21:00"
+#       to: "06:00"
+
+# Groups of MAC or name patterns, for --group and --grouped.
+# groups:
+#   cameras: ["cam-*", "aa:bb:cc:*"]
+`))
+
+// prompt asks for a value, keeping def when the answer is empty.
+func prompt(in *bufio.Reader, out io.Writer, label, def string) string {
+	if def != "" {
+		fmt.Fprintf(out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(out, "%s: ", label)
+	}
+
+	line, err := in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return def
+	}
+
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+
+	return def
+}
+
+var configInitForce bool
+
+func init() { // nolint: gochecknoinits
+	configCmd.AddCommand(configInitCmd)
+
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", configInitForce, "overwrite an existing config file")
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+// ConfigValue is a resolved configuration value and where it came from.
+type ConfigValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Env    string `json:"env"`
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "show the effective configuration and the source of each value",
+	Run: func(cmd *cobra.Command, args []string) {
+		values := resolveConfig(cmd)
+
+		writeOutput(cmd, values, func() {
+			t := table.NewWriter()
+			t.SetStyle(display.StyleDefault)
+			t.SetOutputMirror(cmd.OutOrStdout())
+			t.AppendHeader(table.Row{"Key", "Value", "Source", "Env"})
+
+			for _, v := range values {
+				t.AppendRow(table.Row{v.Key, v.Value, v.Source, v.Env})
+			}
+
+			t.Render()
+		})
+	},
+}
+
+// resolveConfig lists every flag of every command, plus any other keys
+// set in the config file or environment.
+func resolveConfig(cmd *cobra.Command) []ConfigValue {
+	defaults := map[string]string{}
+
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		add := func(f *pflag.Flag) {
+			if f.Name != "help" && f.Name != "config" {
+				defaults[f.Name] = f.DefValue
+			}
+		}
+
+		c.PersistentFlags().VisitAll(add)
+		c.Flags().VisitAll(add)
+
+		for _, sub := range c.Commands() {
+			if sub.Name() != "completion" && sub.Name() != "help" {
+				walk(sub)
+			}
+		}
+	}
+	walk(rootCmd)
+
+	keys := map[string]bool{}
+	for key := range defaults {
+		keys[key] = true
+	}
+
+	for _, key := range viper.AllKeys() {
+		if viper.InConfig(key) || isEnvSet(key) {
+			keys[key] = true
+		}
+	}
+
+	// Values from the environment and config file are copied into the
+	// flags on startup, so a changed flag only counts as set on the command
+	// line when its value differs from both.
+	file := viper.New()
+	if used := viper.ConfigFileUsed(); used != "" {
+		file.SetConfigFile(used)
+		_ = file.ReadInConfig()
+		_ = expandConfigEnv(file)
+	}
+
+	prof, _ := applyProfile(file, profile)
+
+	var values []ConfigValue
+
+	for key := range keys {
+		v := ConfigValue{Key: key, Env: envName(key), Source: "default", Value: defaults[key]}
+
+		env, inEnv := os.LookupEnv(envName(key))
+		f := cmd.Flags().Lookup(key)
+
+		switch {
+		case f != nil && f.Changed && !(inEnv && f.Value.String() == env) &&
+			!(file.InConfig(key) && f.Value.String() == file.GetString(key)):
+			v.Source, v.Value = "flag", f.Value.String()
+		case inEnv:
+			v.Source, v.Value = "env", env
+		case prof != nil && prof.InConfig(key):
+			v.Source, v.Value = "profile "+profile, file.GetString(key)
+		case file.InConfig(key):
+			v.Source, v.Value = "file", file.GetString(key)
+		}
+
+		if isSecret(key) && v.Value != "" {
+			v.Value = "********"
+		}
+
+		values = append(values, v)
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+
+	return values
+}
+
+func isEnvSet(key string) bool {
+	_, ok := os.LookupEnv(envName(key))
+
+	return ok
+}
+
+func isSecret(key string) bool {
+	return strings.Contains(key, "password") || strings.Contains(key, "creds")
+}
+
+func init() { // nolint: gochecknoinits
+	configCmd.AddCommand(configShowCmd)
+}
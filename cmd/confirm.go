@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+var (
+	assumeYes bool
+	dryRun    bool
+	idsStdin  bool
+
+	skipIfSatisfied bool
+)
+
+// addConfirmFlags adds --yes and --dry-run to a command that acts on
+// clients resolved by confirmTargets.
+func addConfirmFlags(c *cobra.Command) {
+	c.Flags().BoolVarP(&assumeYes, "yes", "y", assumeYes, "do not ask for confirmation")
+	c.Flags().BoolVar(&dryRun, "dry-run", dryRun, "show the clients that would be affected, and stop")
+}
+
+// addStdinFlag adds --stdin to a command whose arguments are read by
+// targetIDs.
+func addStdinFlag(c *cobra.Command) {
+	c.Flags().BoolVar(&idsStdin, "stdin", idsStdin,
+		"also read MACs or names from stdin, one per line (# starts a comment), or as a JSON array")
+}
+
+// addSkipFlag adds --skip-unchanged to a block or unblock command.
+func addSkipFlag(c *cobra.Command) {
+	c.Flags().BoolVar(&skipIfSatisfied, "skip-unchanged", skipIfSatisfied,
+		"look up the current block state first and leave out clients already in the desired state")
+}
+
+// targetIDs returns args, followed by the ids read from stdin with --stdin.
+func targetIDs(cmd *cobra.Command, args []string) ([]string, error) {
+	if !idsStdin {
+		return args, nil
+	}
+
+	ids, err := readIDs(cmd.InOrStdin())
+	if err != nil {
+		return nil, fmt.Errorf("reading stdin: %w", err)
+	}
+
+	return append(args, ids...), nil
+}
+
+// readIDs reads a JSON array of strings, or one id per line ignoring blank
+// lines and anything after a #.
+func readIDs(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		var ids []string
+		if err = json.Unmarshal([]byte(trimmed), &ids); err != nil {
+			return nil, err
+		}
+
+		return ids, nil
+	}
+
+	var ids []string
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if ix := strings.Index(line, "#"); ix >= 0 {
+			line = line[:ix]
+		}
+
+		if line = strings.TrimSpace(line); line != "" {
+			ids = append(ids, line)
+		}
+	}
+
+	return ids, nil
+}
+
+// confirmTargets resolves ids to clients and shows what each matched. The
+// clients to act on are returned once the user confirms, or with --yes.
+// With --dry-run, or when the user declines, nil is returned. When nobody
+// can answer a prompt, because stdin is not a terminal or was read for
+// --stdin, the clients are returned without one, so scheduled jobs keep
+// working; destructive actions are refused there unless --yes is given.
+func confirmTargets(cmd *cobra.Command, ses *unifi.Session, action string, ids []string) (*unifi.BatchResult, error) {
+	matched, err := ses.GetMACsByID(ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	known, err := ses.GetMACs()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		targets = unifi.BatchResult{Action: action}
+		seen    = map[unifi.MAC]bool{}
+	)
+
+	t := table.NewWriter()
+	t.SetStyle(display.StyleDefault)
+	t.SetOutputMirror(cmd.ErrOrStderr())
+	t.AppendHeader(table.Row{"Requested", "MAC", "Name", ""})
+
+	for _, id := range ids {
+		if len(matched[id]) == 0 {
+			targets.Unmatched = append(targets.Unmatched, id)
+			t.AppendRow(table.Row{id, "-", "-", "no match"})
+
+			continue
+		}
+
+		note := ""
+		if len(matched[id]) > 1 {
+			note = fmt.Sprintf("%d matches", len(matched[id]))
+		}
+
+		for _, mac := range matched[id] {
+			name := displayNameOf(known, mac)
+			t.AppendRow(table.Row{id, mac, name, note})
+
+			if !seen[mac] {
+				seen[mac] = true
+				targets.Affected = append(targets.Affected, unifi.ActionResult{MAC: mac, Name: name})
+			}
+		}
+	}
+
+	t.Render()
+
+	count := len(targets.Affected)
+
+	if destructive[action] && count > 0 {
+		if err = showRemoved(cmd, ses, targets.Affected); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case count == 0:
+		return nil, fmt.Errorf("no matching clients")
+	case dryRun:
+		infof(cmd, "dry run: would %s %d client(s)\n", action, count)
+
+		return nil, nil
+	case assumeYes:
+		return &targets, nil
+	case idsStdin || !term.IsTerminal(int(os.Stdin.Fd())):
+		if destructive[action] {
+			return nil, fmt.Errorf("refusing to %s without confirmation; pass --yes", action)
+		}
+
+		return &targets, nil
+	}
+
+	if destructive[action] {
+		fmt.Fprintf(cmd.ErrOrStderr(), "this can't be undone; type %q to %s %d client(s): ", action, action, count)
+	} else {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s %d client(s)? [y/N]: ", action, count)
+	}
+
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if !confirmed(action, strings.ToLower(strings.TrimSpace(answer))) {
+		infof(cmd, "not confirmed; nothing done\n")
+
+		return nil, nil
+	}
+
+	return &targets, nil
+}
+
+// destructive actions permanently lose what the controller knows about a
+// client, so what is lost is listed and the action must be typed out to
+// confirm it.
+var destructive = map[string]bool{"forget": true}
+
+func confirmed(action, answer string) bool {
+	if destructive[action] {
+		return answer == action
+	}
+
+	return answer == "y" || answer == "yes"
+}
+
+// showRemoved lists the stored details of the affected clients that would
+// be lost.
+func showRemoved(cmd *cobra.Command, ses *unifi.Session, affected []unifi.ActionResult) error {
+	clients, err := ses.GetAllClients()
+	if err != nil {
+		return err
+	}
+
+	records := map[string]*unifi.Client{}
+	for ix := range clients {
+		records[strings.ToLower(string(clients[ix].MAC))] = &clients[ix]
+	}
+
+	t := table.NewWriter()
+	t.SetStyle(display.StyleDefault)
+	t.SetOutputMirror(cmd.ErrOrStderr())
+	t.SetTitle("Permanently removed")
+	t.AppendHeader(table.Row{"MAC", "Name", "Fixed IP", "Note", "First Seen"})
+
+	for _, a := range affected {
+		c, ok := records[strings.ToLower(string(a.MAC))]
+		if !ok {
+			t.AppendRow(table.Row{a.MAC, orDash(a.Name), "-", "-", "-"})
+
+			continue
+		}
+
+		var fixed string
+		if c.UseFixedIP {
+			fixed = string(c.FixedIP)
+		}
+
+		t.AppendRow(table.Row{a.MAC, orDash(c.Name), orDash(fixed), orDash(c.Note), c.DisplayFirstSeen(displayOptions)})
+	}
+
+	t.Render()
+
+	return nil
+}
+
+// applyTargets applies fn to the confirmed targets in one call, and writes
+// the per-client outcome.
+func applyTargets(cmd *cobra.Command, ses *unifi.Session, targets *unifi.BatchResult, fn func(...unifi.MAC) (string, error)) {
+	result, err := ses.ApplyBatch(*targets, fn)
+
+	writeOutput(cmd, result, func() {
+		t := table.NewWriter()
+		t.SetStyle(display.StyleDefault)
+		t.SetOutputMirror(cmd.OutOrStdout())
+		t.AppendHeader(table.Row{"MAC", "Name", "Result"})
+
+		for _, affected := range result.Affected {
+			status := "ok"
+
+			switch {
+			case affected.Unchanged:
+				status = "unchanged"
+			case !affected.OK:
+				status = "failed: " + affected.Error
+			}
+
+			t.AppendRow(table.Row{affected.MAC, affected.Name, status})
+		}
+
+		t.Render()
+	})
+
+	cobra.CheckErr(err)
+}
+
+// displayNameOf returns the first name known for mac, other than the MAC.
+func displayNameOf(known map[unifi.MAC][]string, mac unifi.MAC) string {
+	for _, name := range known[mac] {
+		if name != mac.String() {
+			return name
+		}
+	}
+
+	return "-"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
 )
 
 var listCmd = &cobra.Command{
@@ -12,15 +14,90 @@ var listCmd = &cobra.Command{
 		ses, err := initSession(cmd)
 		cobra.CheckErr(err)
 
-		devices, err := ses.GetDevices()
+		groups, err := groupsFromConfig()
+		cobra.CheckErr(err)
+
+		filters, err := deviceFilters(groups)
 		cobra.CheckErr(err)
 
-		for _, device := range devices {
-			cmd.Printf("%s\n", device.String())
+		devices, err := ses.GetDevices(filters...)
+		cobra.CheckErr(err)
+
+		if deviceGrouped {
+			grouped := byGroup(groups, devices, groups.DeviceGroup)
+			writeOutput(cmd, grouped, func() {
+				for _, name := range groups.Names() {
+					if len(grouped[name]) == 0 {
+						continue
+					}
+
+					cmd.Printf("\n== %s (%d) ==\n", name, len(grouped[name]))
+
+					for _, device := range grouped[name] {
+						cmd.Printf("%s\n", device.Display(displayOptions))
+					}
+				}
+			})
+
+			return
 		}
+
+		writeOutput(cmd, devices, func() {
+			for _, device := range devices {
+				cmd.Printf("%s\n", device.Display(displayOptions))
+			}
+		})
 	},
 }
 
+// deviceFilters converts the device list flags into DeviceFilters.
+func deviceFilters(groups unifi.Groups) ([]unifi.DeviceFilter, error) {
+	var filters []unifi.DeviceFilter
+
+	if len(deviceGroups) > 0 {
+		if err := checkGroups(groups, deviceGroups); err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, unifi.InDeviceGroup(groups, deviceGroups...))
+	}
+
+	if deviceTypeFilter != "" {
+		filters = append(filters, unifi.DeviceType(deviceTypeFilter))
+	}
+
+	if deviceModelFilter != "" {
+		filters = append(filters, unifi.DeviceModel(deviceModelFilter))
+	}
+
+	if deviceAdoptedOnly {
+		filters = append(filters, unifi.DeviceAdopted)
+	}
+
+	if deviceOfflineOnly {
+		filters = append(filters, unifi.DeviceOffline)
+	}
+
+	return filters, nil
+}
+
+var (
+	deviceGroups      []string
+	deviceGrouped     bool
+	deviceTypeFilter  string
+	deviceModelFilter string
+	deviceAdoptedOnly bool
+	deviceOfflineOnly bool
+)
+
 func init() { // nolint: gochecknoinits
 	deviceCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringSliceVar(&deviceGroups, "group", deviceGroups,
+		"only show devices in these groups (from the groups config)")
+	listCmd.Flags().BoolVar(&deviceGrouped, "grouped", deviceGrouped, "section the output by group")
+	listCmd.Flags().StringVar(&deviceTypeFilter, "type", deviceTypeFilter, "only show devices of this type (e.g. uap, usw, ugw, udm)")
+	listCmd.Flags().StringVar(&deviceModelFilter, "model", deviceModelFilter, `only show devices whose model matches this glob (e.g. "U6*")`)
+	listCmd.Flags().BoolVar(&deviceAdoptedOnly, "adopted", deviceAdoptedOnly, "only show adopted devices")
+	listCmd.Flags().BoolVar(&deviceOfflineOnly, "offline", deviceOfflineOnly, "only show offline devices")
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+var devicePortsCmd = &cobra.Command{
+	Use:   "ports <device>",
+	Short: "show per port link state and traffic statistics of a device",
+	Long: `Show each port of a device, matched by name or MAC, with its link state,
+speed, PoE status, current receive and send rates, and error and drop
+counters. With --output json the port table is written as reported by the
+controller.`,
+	Example: "device ports office-switch",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		devices, err := ses.GetDevices()
+		cobra.CheckErr(err)
+
+		device := findDevice(args[0], devices)
+		if device == nil {
+			cobra.CheckErr(fmt.Errorf("no device %q", args[0]))
+		}
+
+		writeOutput(cmd, device.PortTable, func() {
+			t := table.NewWriter()
+			t.SetStyle(display.StyleDefault)
+			t.SetOutputMirror(cmd.OutOrStdout())
+			t.SetTitle(device.Name)
+			t.AppendHeader(table.Row{
+				"Port", "Name", "Link", "PoE", "Rx Rate", "Tx Rate",
+				"Rx Errors", "Tx Errors", "Rx Dropped", "Tx Dropped",
+			})
+
+			for ix := range device.PortTable {
+				p := &device.PortTable[ix]
+				t.AppendRow(table.Row{
+					p.PortIndex, p.Name, p.LinkSpeed(), p.PoEStatus(), p.DisplayReceiveRate(), p.DisplaySendRate(),
+					p.ReceiveErrors, p.SendErrors, p.ReceiveDropped, p.SendDropped,
+				})
+			}
+
+			t.Render()
+		})
+	},
+}
+
+// findDevice returns the first device whose MAC or name matches query,
+// ignoring case.
+func findDevice(query string, devices []unifi.Device) *unifi.Device {
+	for ix := range devices {
+		d := &devices[ix]
+		if strings.EqualFold(d.MAC.String(), query) || (d.Name != "" && strings.EqualFold(d.Name, query)) {
+			return d
+		}
+	}
+
+	return nil
+}
+
+func init() { // nolint: gochecknoinits
+	deviceCmd.AddCommand(devicePortsCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+var deviceProfilesCmd = &cobra.Command{
+	Use:     "profiles",
+	Aliases: []string{"portconf", "prof"},
+	Short:   "list switch port profiles",
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		profiles, err := ses.GetPortProfiles()
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, profiles, func() {
+			for _, profile := range profiles {
+				cmd.Printf("%s\n", profile.String())
+			}
+		})
+	},
+}
+
+var deviceSetPortCmd = &cobra.Command{
+	Use:     "set-port",
+	Short:   "assign a port profile to a switch port",
+	Example: "<switch> <port-idx> <profile-name-or-id>",
+	Args:    cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		macs, err := ses.GetMACsBy(args[0])
+		cobra.CheckErr(err)
+
+		if len(macs) != 1 {
+			cobra.CheckErr(fmt.Errorf("switch %q matched %d devices", args[0], len(macs)))
+		}
+
+		portIdx, err := strconv.ParseInt(args[1], 10, 64)
+		cobra.CheckErr(err)
+
+		profiles, err := ses.GetPortProfiles()
+		cobra.CheckErr(err)
+
+		profileID := findPortProfile(profiles, args[2])
+		if len(profileID) == 0 {
+			cobra.CheckErr(fmt.Errorf("unknown port profile %q", args[2]))
+		}
+
+		out, err := ses.SetPortProfile(macs[0], portIdx, profileID)
+		cobra.CheckErr(err)
+
+		cmd.Printf("%s\n", out)
+	},
+}
+
+func findPortProfile(profiles []unifi.PortProfile, nameOrID string) string {
+	for _, profile := range profiles {
+		if profile.ID == nameOrID || profile.Name == nameOrID {
+			return profile.ID
+		}
+	}
+
+	return ""
+}
+
+func init() { // nolint: gochecknoinits
+	deviceCmd.AddCommand(deviceProfilesCmd)
+	deviceCmd.AddCommand(deviceSetPortCmd)
+}
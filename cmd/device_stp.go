@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+var deviceSTPCmd = &cobra.Command{
+	Use:     "stp",
+	Aliases: []string{"spanning-tree"},
+	Short:   "show the spanning tree state of switch ports and recent blocking events",
+	Long: `Show the spanning tree state and path cost of each switch port, and a
+timeline of recent STP port blocking events. A blocking port whose link is up
+means there is a loop between switches that STP is holding back; those are
+flagged with "!" unless the redundant link is deliberate.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		status, err := ses.GetSTPStatus()
+		cobra.CheckErr(err)
+
+		if stpBlockingOnly {
+			ports := status.Ports[:0]
+
+			for _, p := range status.Ports {
+				if p.IsBlocking() {
+					ports = append(ports, p)
+				}
+			}
+
+			status.Ports = ports
+		}
+
+		writeOutput(cmd, status, func() {
+			t := table.NewWriter()
+			t.SetStyle(display.StyleDefault)
+			t.SetOutputMirror(cmd.OutOrStdout())
+			t.AppendHeader(table.Row{"", "Device", "Port", "Name", "State", "Path Cost", "Link", "Last Blocked"})
+
+			for _, p := range status.Ports {
+				flag, link, blocked := "", "down", ""
+
+				if p.Unexpected {
+					flag = "!"
+				}
+
+				if p.IsUp {
+					link = "up"
+				}
+
+				if p.LastBlocked != nil {
+					blocked = displayOptions.In(*p.LastBlocked).Format(timeFormat)
+				}
+
+				t.AppendRow(table.Row{flag, p.Device, p.Port, p.PortName, p.State, p.PathCost, link, blocked})
+			}
+
+			t.Render()
+
+			if len(status.Events) == 0 {
+				return
+			}
+
+			cmd.Printf("\nSTP events:\n")
+
+			for _, e := range status.Events {
+				cmd.Printf("%s\n", e.Display(displayOptions))
+			}
+		})
+	},
+}
+
+var stpBlockingOnly bool
+
+func init() { // nolint: gochecknoinits
+	deviceSTPCmd.Flags().BoolVar(&stpBlockingOnly, "blocking", stpBlockingOnly, "only show blocking ports")
+
+	deviceCmd.AddCommand(deviceSTPCmd)
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+// Doctor check results.
+const (
+	checkOK   = "ok"
+	checkFail = "fail"
+	checkSkip = "skipped"
+)
+
+// DoctorCheck is the outcome of one doctor check, with a hint on how to fix
+// a failure.
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "diagnose common problems reaching and using the controller",
+	Long: `Check, in order, that the endpoint resolves and accepts connections, that its
+TLS certificate verifies, that the local clock agrees with the controller,
+that the credentials are set and log in, that the account can read the site,
+and that NATS is reachable. Each failure comes with a hint; checks that
+need a failed one are skipped. Exits non-zero if any check fails.`,
+	// Missing credentials are reported as a failed check instead.
+	PersistentPreRun: credentialsOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := runDoctor(cmd)
+
+		writeOutput(cmd, checks, func() {
+			t := table.NewWriter()
+			t.SetStyle(display.StyleDefault)
+			t.SetOutputMirror(cmd.OutOrStdout())
+			t.AppendHeader(table.Row{"Check", "Result", "Detail"})
+
+			for _, c := range checks {
+				detail := c.Detail
+				if c.Hint != "" {
+					detail += "\nhint: " + c.Hint
+				}
+
+				t.AppendRow(table.Row{c.Name, c.Result, detail})
+			}
+
+			t.Render()
+		})
+
+		for _, c := range checks {
+			if c.Result == checkFail {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// doctor runs checks in order, skipping those whose prerequisites did not
+// pass.
+type doctor struct {
+	checks []DoctorCheck
+	passed map[string]bool
+}
+
+func (d *doctor) check(name string, needs []string, fn func() (detail, hint string, err error)) {
+	for _, need := range needs {
+		if !d.passed[need] {
+			d.checks = append(d.checks, DoctorCheck{Name: name, Result: checkSkip, Detail: "needs " + need})
+
+			return
+		}
+	}
+
+	detail, hint, err := fn()
+	if err != nil {
+		d.checks = append(d.checks, DoctorCheck{Name: name, Result: checkFail, Detail: err.Error(), Hint: hint})
+
+		return
+	}
+
+	d.passed[name] = true
+	d.checks = append(d.checks, DoctorCheck{Name: name, Result: checkOK, Detail: detail})
+}
+
+func runDoctor(cmd *cobra.Command) []DoctorCheck {
+	var (
+		d    = doctor{passed: map[string]bool{}}
+		addr string
+		host string
+	)
+
+	ctx := cmd.Context()
+
+	d.check("endpoint", nil, func() (string, string, error) {
+		var err error
+
+		if addr, err = endpointAddr(endpoint); err != nil {
+			return "", "set --endpoint to the controller URL, e.g. https://192.168.1.1", err
+		}
+
+		host, _, _ = net.SplitHostPort(addr)
+
+		return addr, "", nil
+	})
+
+	d.check("dns", []string{"endpoint"}, func() (string, string, error) {
+		if net.ParseIP(host) != nil {
+			return "address, no lookup needed", "", nil
+		}
+
+		ips, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return "", "check the host name in --endpoint, or use the controller's IP address", err
+		}
+
+		return fmt.Sprintf("%s resolves to %v", host, ips), "", nil
+	})
+
+	d.check("tcp", []string{"dns"}, func() (string, string, error) {
+		conn, err := (&net.Dialer{Timeout: doctorTimeout}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return "", "check the controller is up, the port in --endpoint, and any firewall in between", err
+		}
+
+		defer conn.Close()
+
+		return "connected to " + conn.RemoteAddr().String(), "", nil
+	})
+
+	d.check("tls", []string{"tcp"}, func() (string, string, error) {
+		dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: doctorTimeout}, Config: &tls.Config{ServerName: host}} // nolint: gosec
+
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return "", tlsHint(err), err
+		}
+
+		defer conn.Close()
+
+		state := conn.(*tls.Conn).ConnectionState()
+		leaf := state.PeerCertificates[0]
+
+		return fmt.Sprintf("%s, certificate for %s valid until %s",
+			tls.VersionName(state.Version), leaf.Subject.CommonName, leaf.NotAfter.Format(time.DateOnly)), "", nil
+	})
+
+	d.check("clock", []string{"tcp"}, func() (string, string, error) {
+		skew, err := serverClockSkew(ctx, addr)
+		if err != nil {
+			return "", "", err
+		}
+
+		if maxClockSkew >= 0 && skew.Abs() > maxClockSkew {
+			return "", "sync the local clock (e.g. enable NTP); a wrong clock breaks certificate checks and sessions",
+				fmt.Errorf("local clock differs from the controller by %s", skew.Round(time.Second))
+		}
+
+		return fmt.Sprintf("within %s of the controller", skew.Abs().Round(time.Second)), "", nil
+	})
+
+	d.check("credentials", nil, func() (string, string, error) {
+		if username == "" || password == "" {
+			return "", "set --username and --password, UNIFI_USERNAME and UNIFI_PASSWORD, or the config file",
+				errors.New("username or password is not set")
+		}
+
+		return "username " + username, "", nil
+	})
+
+	b := newSessionBuilder(cmd)
+	b.NATSURL = ""
+
+	ses, buildErr := b.Build()
+
+	d.check("login", []string{"tls", "credentials"}, func() (string, string, error) {
+		if buildErr != nil {
+			return "", "", buildErr
+		}
+
+		if _, err := ses.Login(); err != nil {
+			return "", "check the credentials; a local account is needed, as cloud (SSO) accounts with 2FA can't log in", err
+		}
+
+		return "logged in", "", nil
+	})
+
+	d.check("api", []string{"login"}, func() (string, string, error) {
+		health, err := ses.GetHealth()
+		if err != nil {
+			return "", "check the site exists and the account has at least view access to the Network application", err
+		}
+
+		return fmt.Sprintf("read %d subsystems", len(health)), "", nil
+	})
+
+	d.check("nats", nil, func() (string, string, error) {
+		if natsURL == "" {
+			return "disabled", "", nil
+		}
+
+		opts := []nats.Option{nats.Timeout(natsConnTimeout)}
+		if natsCreds != "" {
+			opts = append(opts, nats.UserCredentials(natsCreds))
+		}
+
+		nc, err := nats.Connect(natsURL, opts...)
+		if err != nil {
+			return "", "check nats_url and nats_creds in the config or environment; logs are mirrored to NATS when it is set", err
+		}
+
+		defer nc.Close()
+
+		return "connected to " + nc.ConnectedUrlRedacted(), "", nil
+	})
+
+	return d.checks
+}
+
+// tlsHint suggests a fix for a failed TLS handshake.
+func tlsHint(err error) string {
+	var (
+		unknown  x509.UnknownAuthorityError
+		invalid  x509.CertificateInvalidError
+		hostname x509.HostnameError
+	)
+
+	switch {
+	case errors.As(err, &unknown):
+		return "the controller's certificate is self-signed or from an unknown CA; install a trusted certificate or add its CA to the system trust store"
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		return "the certificate is expired or not yet valid; renew it, and check the local clock"
+	case errors.As(err, &hostname):
+		return "the certificate doesn't name this host; use the name it was issued for in --endpoint"
+	default:
+		return "check the endpoint serves HTTPS"
+	}
+}
+
+// serverClockSkew returns how far the local clock is ahead of the Date
+// header returned by the server at addr.
+func serverClockSkew(ctx context.Context, addr string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://"+addr+"/", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // nolint: gosec // only the Date header is used
+	}}
+
+	before := time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	resp.Body.Close()
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("no usable Date header: %w", err)
+	}
+
+	// Date has a resolution of a second; compare against the midpoint of
+	// the request.
+	now := before.Add(time.Since(before) / 2)
+
+	return now.Sub(date), nil
+}
+
+var doctorTimeout = 5 * time.Second
+
+func init() { // nolint: gochecknoinits
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", doctorTimeout, "timeout for each network check")
+
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -36,7 +36,7 @@ var eventConnectionsCmd = &cobra.Command{
 			return string(mac), false
 		}
 
-		display.EventsTable(cmd.OutOrStdout(), checkGetName, events).Render()
+		display.EventsTable(cmd.OutOrStdout(), displayOptions, checkGetName, events).Render()
 	},
 }
 
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+// eventCursor records the newest event already emitted, and the IDs of the
+// events at that time, so the next run only emits newer events.
+type eventCursor struct {
+	Time time.Time `json:"time"`
+	IDs  []string  `json:"ids,omitempty"`
+}
+
+// readEventCursor reads the cursor at path; a missing file is the zero
+// cursor, before every event.
+func readEventCursor(path string) (eventCursor, error) {
+	var c eventCursor
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+
+	if err != nil {
+		return c, err
+	}
+
+	if err = json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("reading event cursor %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// writeEventCursor atomically replaces the file at path with c.
+func writeEventCursor(path string, c eventCursor) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// newer returns the events after the cursor, in their original order.
+func (c eventCursor) newer(events []unifi.Event) []unifi.Event {
+	seen := map[string]bool{}
+	for _, id := range c.IDs {
+		seen[id] = true
+	}
+
+	var out []unifi.Event
+
+	for _, evt := range events {
+		t := eventTime(evt)
+		if t.After(c.Time) || (t.Equal(c.Time) && !seen[evt.ID]) {
+			out = append(out, evt)
+		}
+	}
+
+	return out
+}
+
+// advance returns the cursor moved past events.
+func (c eventCursor) advance(events []unifi.Event) eventCursor {
+	for _, evt := range events {
+		switch t := eventTime(evt); {
+		case t.After(c.Time):
+			c = eventCursor{Time: t, IDs: []string{evt.ID}}
+		case t.Equal(c.Time):
+			c.IDs = append(c.IDs, evt.ID)
+		}
+	}
+
+	return c
+}
+
+// eventTime is when evt happened, from its datetime or, failing that, its
+// time field.
+func eventTime(evt unifi.Event) time.Time {
+	if !evt.DateTime.IsZero() {
+		return evt.DateTime
+	}
+
+	return evt.TimeStamp.Time()
+}
@@ -1,10 +1,19 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/notify"
 )
 
-var allEvents bool
+var (
+	allEvents       bool
+	eventSyslog     string
+	eventCEF        bool
+	eventCursorFile string
+)
 
 var eventListCmd = &cobra.Command{
 	Use:     "list",
@@ -22,8 +31,46 @@ var eventListCmd = &cobra.Command{
 		events, err := fetch()
 		cobra.CheckErr(err)
 
-		for _, event := range events {
-			cmd.Printf("%s\n", event.String())
+		var cursor eventCursor
+
+		if eventCursorFile != "" {
+			cursor, err = readEventCursor(eventCursorFile)
+			cobra.CheckErr(err)
+
+			events = cursor.newer(events)
+		}
+
+		if eventSyslog != "" {
+			sink := &notify.Syslog{URL: eventSyslog}
+
+			for _, evt := range events {
+				msg := eventMessage(evt)
+				if eventCEF {
+					msg.Body = evt.CEF(build.Version)
+				}
+
+				if err = sink.Notify(cmd.Context(), msg); err != nil {
+					cobra.CheckErr(fmt.Errorf("forwarding %s to syslog: %w", evt.Key, err))
+				}
+			}
+
+			infof(cmd, "forwarded %d event(s) to syslog\n", len(events))
+		}
+
+		writeOutput(cmd, events, func() {
+			for _, event := range events {
+				if eventCEF {
+					cmd.Printf("%s\n", event.CEF(build.Version))
+
+					continue
+				}
+
+				cmd.Printf("%s\n", event.Display(displayOptions))
+			}
+		})
+
+		if eventCursorFile != "" && len(events) > 0 {
+			cobra.CheckErr(writeEventCursor(eventCursorFile, cursor.advance(events)))
 		}
 	},
 }
@@ -32,4 +79,10 @@ func init() { // nolint: gochecknoinits
 	eventCmd.AddCommand(eventListCmd)
 
 	eventListCmd.Flags().BoolVar(&allEvents, "all", allEvents, "show all events")
+	eventListCmd.Flags().StringVar(&eventSyslog, "syslog", eventSyslog,
+		`also send the events to syslog as RFC 5424 messages: udp://host:514, tcp://host:514, unix:///dev/log, or "local"`)
+	eventListCmd.Flags().BoolVar(&eventCEF, "cef", eventCEF,
+		"print the events, and send them to --syslog, in ArcSight Common Event Format")
+	eventListCmd.Flags().StringVar(&eventCursorFile, "cursor-file", eventCursorFile,
+		"only show events newer than those shown by the last run with this file, and record the newest")
 }
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/exporter"
+)
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "serve per-client and per-device Prometheus metrics",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", exporter.New(ses, exporter.OptCacheTTL(exporterCacheTTL)))
+
+		srv := &http.Server{ // nolint:exhaustivestruct
+			Addr:              exporterListen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			<-ctx.Done()
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+
+		infof(cmd, "serving metrics on %s/metrics\n", exporterListen)
+
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			cobra.CheckErr(err)
+		}
+	},
+}
+
+var (
+	exporterListen   = ":9130"
+	exporterCacheTTL = exporter.DefaultCacheTTL
+)
+
+func init() { // nolint: gochecknoinits
+	rootCmd.AddCommand(exporterCmd)
+
+	exporterCmd.Flags().StringVar(&exporterListen, "listen", exporterListen, "address to serve /metrics on")
+	exporterCmd.Flags().DurationVar(&exporterCacheTTL, "cache", exporterCacheTTL,
+		"reuse fetched data for this long; set to the scrape interval")
+}
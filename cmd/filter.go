@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+// parseClientFilters converts --filter expressions into ClientFilters.
+//
+// Supported expressions:
+//
+//	in <cidr>      client IP within the subnet
+//	blocked        blocked clients
+//	guest          guest clients
+//	wired          wired clients
+//	authorized     authorized clients
+//	active         clients connected now (see Client.ConnectionState)
+//	uptime <dur>   clients connected for at least the duration, e.g. 24h
+//	new <dur>      clients first seen within the duration, e.g. 1h
+//
+// Any expression may be negated with a leading "not " or "!".
+func parseClientFilters(exprs []string) ([]unifi.ClientFilter, error) {
+	var filters []unifi.ClientFilter
+
+	for _, expr := range exprs {
+		filter, err := parseClientFilter(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func parseClientFilter(expr string) (unifi.ClientFilter, error) {
+	fields := strings.Fields(strings.ToLower(expr))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty filter")
+	}
+
+	switch {
+	case fields[0] == "not":
+		inner, err := parseClientFilter(strings.Join(fields[1:], " "))
+		if err != nil {
+			return nil, err
+		}
+
+		return unifi.Not(inner), nil
+
+	case strings.HasPrefix(fields[0], "!"):
+		inner, err := parseClientFilter(strings.TrimPrefix(strings.Join(fields, " "), "!"))
+		if err != nil {
+			return nil, err
+		}
+
+		return unifi.Not(inner), nil
+	}
+
+	args := fields[1:]
+
+	switch fields[0] {
+	case "in":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("filter %q: expected \"in <cidr>\"", expr)
+		}
+
+		subnet, err := unifi.ParseSubnet(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("filter %q: %w", expr, err)
+		}
+
+		return unifi.InSubnet(subnet, displayOptions), nil
+
+	case "uptime", "new":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("filter %q: expected \"%s <duration>\"", expr, fields[0])
+		}
+
+		d, err := time.ParseDuration(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("filter %q: %w", expr, err)
+		}
+
+		if fields[0] == "new" {
+			return unifi.NewClient(d), nil
+		}
+
+		return unifi.UptimeAtLeast(d), nil
+
+	case "blocked":
+		return unifi.Blocked, nil
+	case "guest":
+		return unifi.Guest, nil
+	case "wired":
+		return unifi.Wired, nil
+	case "authorized":
+		return unifi.Authorized, nil
+	case "active":
+		return unifi.Active, nil
+	}
+
+	return nil, fmt.Errorf("unknown filter %q", expr)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+const groupsKey = "groups"
+
+// groupsFromConfig reads the group definitions, e.g.
+//
+//	groups:
+//	  cameras: ["cam-*", "aa:bb:cc:*"]
+//	  kids: ["kids-*"]
+func groupsFromConfig() (unifi.Groups, error) {
+	groups := unifi.Groups{}
+
+	if err := viper.UnmarshalKey(groupsKey, &groups); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", groupsKey, err)
+	}
+
+	return groups, nil
+}
+
+// groupFilters returns a filter matching clients in any of the named groups,
+// or none when no names are given.
+func groupFilters(groups unifi.Groups, names []string) ([]unifi.ClientFilter, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	if err := checkGroups(groups, names); err != nil {
+		return nil, err
+	}
+
+	return []unifi.ClientFilter{unifi.InGroup(groups, names...)}, nil
+}
+
+// checkGroups reports the first of names that is not a configured group.
+func checkGroups(groups unifi.Groups, names []string) error {
+	for _, name := range names {
+		if _, ok := groups[name]; !ok && name != unifi.Ungrouped {
+			return fmt.Errorf("unknown group %q", name)
+		}
+	}
+
+	return nil
+}
+
+// byGroup sections items by group name, for structured output.
+func byGroup[T any](groups unifi.Groups, items []T, group func(*T) string) map[string][]T {
+	out := map[string][]T{}
+	for ix := range items {
+		name := group(&items[ix])
+		out[name] = append(out[name], items[ix])
+	}
+
+	return out
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var healthCmd = &cobra.Command{
+	Use:     "health",
+	Aliases: []string{"hl"},
+	Short:   "show controller subsystem health",
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		health, err := ses.GetHealth()
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, health, func() {
+			for _, h := range health {
+				cmd.Printf("%s\n", h.Display(displayOptions))
+			}
+		})
+
+		ok := true
+		for _, h := range health {
+			ok = ok && (h.IsOK() || h.Status == "unknown")
+		}
+
+		if !ok {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() { // nolint: gochecknoinits
+	rootCmd.AddCommand(healthCmd)
+}
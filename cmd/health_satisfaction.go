@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+var healthSatisfactionCmd = &cobra.Command{
+	Use:     "satisfaction",
+	Aliases: []string{"experience", "sat"},
+	Short:   "show average client satisfaction and the worst clients and access points",
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		summary, err := ses.GetSatisfactionSummary(satisfactionWorst)
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, summary, func() {
+			cmd.Printf("%s\n", summary.String())
+
+			for _, section := range []struct {
+				title  string
+				scores []unifi.SatisfactionScore
+			}{
+				{"Worst clients", summary.WorstClients},
+				{"Worst access points", summary.WorstAccessPoints},
+			} {
+				if len(section.scores) == 0 {
+					continue
+				}
+
+				t := table.NewWriter()
+				t.SetStyle(display.StyleDefault)
+				t.SetOutputMirror(cmd.OutOrStdout())
+				t.SetTitle(section.title)
+				t.AppendHeader(table.Row{"Name", "MAC", "Satisfaction"})
+
+				for _, score := range section.scores {
+					t.AppendRow(table.Row{score.Name, score.MAC, score.Satisfaction})
+				}
+
+				t.Render()
+			}
+		})
+	},
+}
+
+var satisfactionWorst = unifi.DefaultSatisfactionWorst
+
+func init() { // nolint: gochecknoinits
+	healthCmd.AddCommand(healthSatisfactionCmd)
+
+	healthSatisfactionCmd.Flags().IntVar(&satisfactionWorst, "worst", satisfactionWorst, "number of worst clients and access points to list")
+}
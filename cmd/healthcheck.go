@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/nats"
+)
+
+var healthcheckCmd = &cobra.Command{
+	Use:   "healthcheck",
+	Short: "exit zero if the agent refreshed from the controller recently",
+	Long: `Exit zero if the heartbeat file written by "nats agent --heartbeat-file"
+records a successful client refresh within --max-age, and non-zero otherwise.
+The controller is not queried, so this is cheap enough for a container
+HEALTHCHECK:
+
+  HEALTHCHECK CMD ["/unifi-scheduler", "healthcheck", "--heartbeat-file", "/tmp/heartbeat"]`,
+	// The controller is not queried, so the credentials are not required.
+	PersistentPreRun: credentialsOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		if heartbeatFile == "" {
+			cobra.CheckErr(errors.New("--heartbeat-file is required"))
+		}
+
+		last, err := nats.ReadHeartbeat(heartbeatFile)
+		if err != nil {
+			cmd.PrintErrf("unhealthy: %v\n", err)
+			os.Exit(1)
+		}
+
+		age := time.Since(last).Round(time.Second)
+		if age > healthcheckMaxAge {
+			cmd.PrintErrf("unhealthy: last refresh %s ago, more than %s\n", age, healthcheckMaxAge)
+			os.Exit(1)
+		}
+
+		infof(cmd, "healthy: last refresh %s ago\n", age)
+	},
+}
+
+const heartbeatFileFlag = "heartbeat-file"
+
+var (
+	heartbeatFile     = ""
+	healthcheckMaxAge = 3 * time.Minute
+)
+
+func init() { // nolint: gochecknoinits
+	healthcheckCmd.Flags().StringVar(&heartbeatFile, heartbeatFileFlag, heartbeatFile,
+		"heartbeat file written by the nats agent")
+	healthcheckCmd.Flags().DurationVar(&healthcheckMaxAge, "max-age", healthcheckMaxAge,
+		"maximum time since the last successful refresh to be healthy")
+
+	rootCmd.AddCommand(healthcheckCmd)
+}
@@ -4,6 +4,8 @@ import (
 	_ "net/http/pprof"
 
 	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/nats"
 )
 
 var natsCmd = &cobra.Command{
@@ -19,10 +21,24 @@ const (
 )
 
 var (
-	natsURL   = "nats://localhost:4222"
-	natsCreds = ""
+	natsURL         = "nats://localhost:4222"
+	natsCreds       = ""
+	natsConnTimeout = nats.DefaultConnectTimeout
+	natsOpTimeout   = nats.DefaultOpTimeout
+	streamReplicas  = nats.DefaultStreamConfig.Replicas
+	kvReplicas      = nats.DefaultBucketConfig.Replicas
 )
 
+// natsClientOpts returns the connection options shared by all nats commands.
+func natsClientOpts() []nats.ClientOpt {
+	return []nats.ClientOpt{
+		nats.OptNATSUrl(natsURL),
+		nats.OptCreds(natsCreds),
+		nats.OptConnectTimeout(natsConnTimeout),
+		nats.OptOpTimeout(natsOpTimeout),
+	}
+}
+
 func init() { // nolint: gochecknoinits
 	pf := natsCmd.PersistentFlags()
 
@@ -31,5 +47,10 @@ func init() { // nolint: gochecknoinits
 
 	pf.StringVar(&natsCreds, natsCredsFlag, natsCreds, "NATS Credentials File")
 
+	pf.DurationVar(&natsConnTimeout, "nats-conn-timeout", natsConnTimeout, "timeout for connecting to NATS")
+	pf.DurationVar(&natsOpTimeout, "nats-op-timeout", natsOpTimeout, "timeout for each JetStream or KV operation")
+	pf.IntVar(&streamReplicas, "stream-replicas", streamReplicas, "replica count for created JetStream streams")
+	pf.IntVar(&kvReplicas, "kv-replicas", kvReplicas, "replica count for created KV buckets")
+
 	rootCmd.AddCommand(natsCmd)
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/johnweldon/unifi-scheduler/pkg/nats"
+	"github.com/johnweldon/unifi-scheduler/pkg/store"
 )
 
 var natsAgentCmd = &cobra.Command{
@@ -16,16 +18,59 @@ var natsAgentCmd = &cobra.Command{
 	Aliases: []string{"agt", "a"},
 	Short:   "nats agent",
 	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Printf("Version: %s\n", Version)
+		infof(cmd, "Version: %s\n", Version)
 
 		ctx, _ := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
 
 		ses, err := initSession(cmd)
 		cobra.CheckErr(err)
 
-		opts := []nats.ClientOpt{nats.OptNATSUrl(natsURL), nats.OptCreds(natsCreds)}
+		opts := append(natsClientOpts(),
+			nats.OptStreamConfig(agentStreamConfig),
+			nats.OptBucketConfig(agentBucketConfig),
+			nats.OptStreamReplicas(streamReplicas),
+			nats.OptBucketReplicas(kvReplicas),
+		)
 
 		a := nats.NewAgent(ses, baseSubject, opts...)
+		a.Init(
+			nats.OptStartupJitter(agentStartupJitter),
+			nats.OptAlignedTicks(agentAlignTicks),
+			nats.OptPublishQueue(agentPublishQueue),
+		)
+
+		n := notifierFromConfig()
+		if n != nil {
+			a.Init(
+				nats.OptNotifier(n),
+				nats.OptNotifySeverity(notifySeverityFromConfig()),
+				nats.OptAnomalySpike(agentAnomalySpike),
+			)
+		}
+
+		if agentStore != "" {
+			st, err := store.Open(agentStore)
+			cobra.CheckErr(err)
+
+			// The agent closes the store once its writes are done.
+			a.Init(nats.OptStore(st))
+
+			if natsURL == "" {
+				a.Init(nats.OptWithoutNATS())
+			}
+		}
+
+		if heartbeatFile != "" {
+			a.Init(nats.OptHeartbeatFile(heartbeatFile))
+		}
+
+		monitor, err := presenceFromConfig(n)
+		cobra.CheckErr(err)
+
+		if monitor != nil {
+			a.Init(nats.OptPresenceMonitor(monitor))
+		}
+
 		cobra.CheckErr(a.Start(ctx))
 
 		markInterval := time.After(1 * time.Second)
@@ -34,14 +79,22 @@ var natsAgentCmd = &cobra.Command{
 		for {
 			select {
 			case <-ctx.Done():
-				cmd.Printf("quitting...\n")
+				infof(cmd, "quitting...\n")
+
+				stopCtx, cancel := context.WithTimeout(context.Background(), agentStopTimeout)
+				defer cancel()
+
+				if err := a.Stop(stopCtx); err != nil {
+					cmd.PrintErrf("error: stopping agent: %v\n", err)
+				}
+
 				return
 			case <-markInterval:
 				markInterval = time.After(1 * time.Minute)
-				cmd.Printf(".")
+				infof(cmd, ".")
 			case <-hourInterval:
 				hourInterval = time.After(1 * time.Hour)
-				cmd.Printf("H\n")
+				cmd.Printf("H dropped=%d nats=%s\n", a.Dropped(), a.ConnectionStatus())
 			}
 
 			os.Stdout.Sync()
@@ -49,6 +102,46 @@ var natsAgentCmd = &cobra.Command{
 	},
 }
 
+var (
+	agentStartupJitter time.Duration
+	agentAlignTicks    bool
+	agentStopTimeout   = 30 * time.Second
+	agentPublishQueue  = 1024
+	agentStore         = ""
+	agentAnomalySpike  = nats.DefaultAnomalySpike
+	agentStreamConfig  = nats.DefaultStreamConfig
+	agentBucketConfig  = nats.DefaultBucketConfig
+)
+
 func init() { // nolint: gochecknoinits
 	natsCmd.AddCommand(natsAgentCmd)
+
+	natsAgentCmd.Flags().DurationVar(&agentStartupJitter, "startup-jitter", agentStartupJitter,
+		"delay the first poll by a random duration up to this value")
+	natsAgentCmd.Flags().BoolVar(&agentAlignTicks, "align-ticks", agentAlignTicks,
+		"poll on a fixed wall-clock grid instead of a fixed delay after each poll")
+	natsAgentCmd.Flags().DurationVar(&agentStopTimeout, "stop-timeout", agentStopTimeout,
+		"maximum time to wait for in-flight work on shutdown")
+	natsAgentCmd.Flags().IntVar(&agentPublishQueue, "publish-queue", agentPublishQueue,
+		"number of pending publishes to buffer before dropping the oldest (0 publishes synchronously)")
+
+	natsAgentCmd.Flags().Int64Var(&agentAnomalySpike, "anomaly-spike", agentAnomalySpike,
+		"notify when an anomaly count grows by this much between polls (0 disables)")
+
+	natsAgentCmd.Flags().StringVar(&heartbeatFile, heartbeatFileFlag, heartbeatFile,
+		"write the time of each successful client refresh to this file, for healthcheck")
+
+	natsAgentCmd.Flags().StringVar(&agentStore, "store", agentStore,
+		"also persist snapshots and events locally, e.g. sqlite:history.db (with --nats_url='' NATS is not used)")
+
+	natsAgentCmd.Flags().DurationVar(&agentStreamConfig.MaxAge, "stream-max-age", agentStreamConfig.MaxAge,
+		"discard stream messages older than this (0 is unlimited)")
+	natsAgentCmd.Flags().Int64Var(&agentStreamConfig.MaxBytes, "stream-max-bytes", agentStreamConfig.MaxBytes,
+		"maximum size of the event stream in bytes (0 is unlimited)")
+	natsAgentCmd.Flags().Int64Var(&agentStreamConfig.MaxMsgs, "stream-max-msgs", agentStreamConfig.MaxMsgs,
+		"maximum number of messages in the event stream (0 is unlimited)")
+	natsAgentCmd.Flags().DurationVar(&agentBucketConfig.TTL, "kv-ttl", agentBucketConfig.TTL,
+		"expire KV entries older than this (0 is unlimited)")
+	natsAgentCmd.Flags().Int64Var(&agentBucketConfig.MaxBytes, "kv-max-bytes", agentBucketConfig.MaxBytes,
+		"maximum size of each KV bucket in bytes (0 is unlimited)")
 }
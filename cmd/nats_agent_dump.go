@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/nats"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+var natsAgentDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "print data stored by the agent, without contacting the controller",
+	Run: func(cmd *cobra.Command, args []string) {
+		s := nats.NewSubscriber(natsClientOpts()...)
+		defer s.Close(context.Background()) // nolint:errcheck
+
+		bucket, key := dumpBucketKey(dumpBucket, dumpKey)
+
+		var raw json.RawMessage
+		cobra.CheckErr(s.Get(bucket, key, &raw))
+
+		if bucket != nats.DetailBucket(baseSubject) {
+			key = ""
+		}
+
+		switch key {
+		case nats.ActiveKey:
+			var into []unifi.Client
+			cobra.CheckErr(json.Unmarshal(raw, &into))
+			writeOutput(cmd, into, func() { display.ClientsTable(cmd.OutOrStdout(), displayOptions, into).Render() })
+		case nats.EventsKey:
+			var into []unifi.Event
+			cobra.CheckErr(json.Unmarshal(raw, &into))
+			unifi.DefaultEventSort.Sort(into)
+			noName := func(mac unifi.MAC) (string, bool) { return string(mac), false }
+			writeOutput(cmd, into, func() { display.EventsTable(cmd.OutOrStdout(), displayOptions, noName, into).Render() })
+		case nats.DevicesKey:
+			var into []unifi.Device
+			cobra.CheckErr(json.Unmarshal(raw, &into))
+			writeOutput(cmd, into, func() {
+				for _, device := range into {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s\n", device.Display(displayOptions))
+				}
+			})
+		default:
+			// No table layout for arbitrary values; fall back to JSON.
+			var into any
+			cobra.CheckErr(json.Unmarshal(raw, &into))
+			writeOutput(cmd, into, func() {
+				cobra.CheckErr(display.Write(cmd.OutOrStdout(), display.FormatJSON, nil, into))
+			})
+		}
+	},
+}
+
+var (
+	dumpBucket = "details"
+	dumpKey    = nats.ActiveKey
+)
+
+// dumpBucketKey maps the short bucket names used by the agent to the
+// full bucket name, normalizing the key the way the agent stores it.
+// Any other bucket name is used as is.
+func dumpBucketKey(bucket, key string) (string, string) {
+	switch bucket {
+	case "details":
+		return nats.DetailBucket(baseSubject), nats.NormalizeKey(key)
+	case "bymac":
+		return nats.ByMACBucket(baseSubject), nats.NormalizeKey(key)
+	case "byname":
+		return nats.ByNameBucket(baseSubject), nats.NormalizeKey(key)
+	default:
+		return bucket, key
+	}
+}
+
+func init() { // nolint: gochecknoinits
+	natsAgentCmd.AddCommand(natsAgentDumpCmd)
+
+	natsAgentDumpCmd.Flags().StringVar(&dumpBucket, "bucket", dumpBucket,
+		"bucket to read (details, bymac, byname, or a full bucket name)")
+	natsAgentDumpCmd.Flags().StringVar(&dumpKey, "key", dumpKey,
+		"key to read (active, devices, events, known, or a MAC or name for bymac/byname)")
+}
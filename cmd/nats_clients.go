@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
 	"github.com/johnweldon/unifi-scheduler/pkg/nats"
@@ -13,13 +15,13 @@ var natsClientsCmd = &cobra.Command{
 	Aliases: []string{"client", "cl", "c"},
 	Short:   "show active clients",
 	Run: func(cmd *cobra.Command, args []string) {
-		opts := []nats.ClientOpt{nats.OptNATSUrl(natsURL), nats.OptCreds(natsCreds)}
-		s := nats.NewSubscriber(opts...)
+		s := nats.NewSubscriber(natsClientOpts()...)
+		defer s.Close(context.Background()) // nolint:errcheck
 
 		var into []unifi.Client
 		cobra.CheckErr(s.Get(nats.DetailBucket(baseSubject), nats.ActiveKey, &into))
 
-		display.ClientsTable(cmd.OutOrStdout(), into).Render()
+		writeOutput(cmd, into, func() { display.ClientsTable(cmd.OutOrStdout(), displayOptions, into).Render() })
 	},
 }
 
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
 	"github.com/johnweldon/unifi-scheduler/pkg/nats"
@@ -13,8 +15,8 @@ var natsConnectionsCmd = &cobra.Command{
 	Aliases: []string{"conn"},
 	Short:   "show connection events",
 	Run: func(cmd *cobra.Command, args []string) {
-		opts := []nats.ClientOpt{nats.OptNATSUrl(natsURL), nats.OptCreds(natsCreds)}
-		s := nats.NewSubscriber(opts...)
+		s := nats.NewSubscriber(natsClientOpts()...)
+		defer s.Close(context.Background()) // nolint:errcheck
 
 		detailBucket := nats.DetailBucket(baseSubject)
 
@@ -42,7 +44,7 @@ var natsConnectionsCmd = &cobra.Command{
 			return names[0], true
 		}
 
-		display.EventsTable(cmd.OutOrStdout(), checkGetName, into).Render()
+		display.EventsTable(cmd.OutOrStdout(), displayOptions, checkGetName, into).Render()
 	},
 }
 
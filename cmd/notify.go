@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/notify"
+	"github.com/johnweldon/unifi-scheduler/pkg/presence"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+const (
+	notifyWebhookURLKey   = "notify.webhook.url"
+	notifySlackURLKey     = "notify.slack.webhook_url"
+	notifySlackChannelKey = "notify.slack.channel"
+	notifyEmailAddrKey    = "notify.email.addr"
+	notifyEmailUserKey    = "notify.email.username"
+	notifyEmailPassKey    = "notify.email.password"
+	notifyEmailFromKey    = "notify.email.from"
+	notifyEmailToKey      = "notify.email.to"
+	notifySyslogURLKey    = "notify.syslog.url"
+	notifySeverityKey     = "notify.severity"
+
+	presenceKey = "presence"
+)
+
+// notifierFromConfig builds the configured notifiers, or returns nil if
+// none are configured.
+func notifierFromConfig() notify.Notifier {
+	var notifiers notify.Multi
+
+	if u := viper.GetString(notifyWebhookURLKey); len(u) > 0 {
+		notifiers = append(notifiers, &notify.Webhook{URL: u})
+	}
+
+	if u := viper.GetString(notifySlackURLKey); len(u) > 0 {
+		notifiers = append(notifiers, &notify.Slack{
+			WebhookURL: u,
+			Channel:    viper.GetString(notifySlackChannelKey),
+			Username:   "unifi-scheduler",
+		})
+	}
+
+	if addr := viper.GetString(notifyEmailAddrKey); len(addr) > 0 {
+		notifiers = append(notifiers, &notify.Email{
+			Addr:     addr,
+			Username: viper.GetString(notifyEmailUserKey),
+			Password: viper.GetString(notifyEmailPassKey),
+			From:     viper.GetString(notifyEmailFromKey),
+			To:       viper.GetStringSlice(notifyEmailToKey),
+		})
+	}
+
+	if u := viper.GetString(notifySyslogURLKey); len(u) > 0 {
+		notifiers = append(notifiers, &notify.Syslog{URL: u})
+	}
+
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	return notifiers
+}
+
+// eventMessage is the notification for evt.
+func eventMessage(evt unifi.Event) notify.Message {
+	return notify.Message{
+		Title:    string(evt.Key),
+		Body:     evt.Message,
+		Severity: evt.Severity().String(),
+		Time:     evt.DateTime,
+		Fields:   evt.Details(),
+	}
+}
+
+// notifySeverityFromConfig returns the minimum severity to notify on.
+func notifySeverityFromConfig() unifi.EventSeverity {
+	switch strings.ToLower(viper.GetString(notifySeverityKey)) {
+	case "info":
+		return unifi.SeverityInfo
+	case "critical":
+		return unifi.SeverityCritical
+	default:
+		return unifi.SeverityWarning
+	}
+}
+
+type presenceConfig struct {
+	Unknown bool            `mapstructure:"unknown"`
+	Allow   []string        `mapstructure:"allow"`
+	Rules   []presence.Rule `mapstructure:"rules"`
+}
+
+// presenceFromConfig builds the presence monitor, or returns nil if no
+// presence rules are configured.
+func presenceFromConfig(n notify.Notifier) (*presence.Monitor, error) {
+	var cfg presenceConfig
+	if err := viper.UnmarshalKey(presenceKey, &cfg); err != nil {
+		return nil, err
+	}
+
+	if !cfg.Unknown && len(cfg.Rules) == 0 {
+		return nil, nil
+	}
+
+	for ix, rule := range cfg.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("presence rule %d (%s): %w", ix+1, rule.Name, err)
+		}
+	}
+
+	return &presence.Monitor{
+		Rules:        cfg.Rules,
+		Notifier:     n,
+		AlertUnknown: cfg.Unknown,
+		Allow:        cfg.Allow,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // so --timezone works in containers without zoneinfo
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+const (
+	outputFlag    = "output"
+	fieldsFlag    = "fields"
+	jqFlag        = "jq"
+	unitsFlag     = "units"
+	rateUnitsFlag = "rate-units"
+	timezoneFlag  = "timezone"
+	absTimeFlag   = "absolute-time"
+	timeFmtFlag   = "time-format"
+	rfc3339Flag   = "rfc3339-times"
+	displayIPFlag = "display-ip"
+)
+
+var (
+	outputFormat = display.FormatTable
+	outputFields []string
+	outputQuery  string
+	sizeUnits    = "si"
+	rateUnits    = "bits"
+	timezone     = ""
+	absoluteTime = false
+	timeFormat   = time.RFC3339
+	rfc3339Times = false
+	displayIP    = unifi.PreferLeasedIP
+
+	// displayOptions collects the display flags for the table output.
+	displayOptions unifi.DisplayOptions
+)
+
+// initDisplayUnits applies the --units and --rate-units flags.
+func initDisplayUnits() {
+	switch sizeUnits {
+	case "si":
+		unifi.DisplayUnits.IEC = false
+	case "iec":
+		unifi.DisplayUnits.IEC = true
+	default:
+		cobra.CheckErr(fmt.Errorf("unsupported units %q (one of si, iec)", sizeUnits))
+	}
+
+	switch rateUnits {
+	case "bits":
+		unifi.DisplayUnits.RateBytes = false
+	case "bytes":
+		unifi.DisplayUnits.RateBytes = true
+	default:
+		cobra.CheckErr(fmt.Errorf("unsupported rate units %q (one of bits, bytes)", rateUnits))
+	}
+}
+
+// initDisplayIP applies the --display-ip flag.
+func initDisplayIP() {
+	switch displayIP {
+	case unifi.PreferLeasedIP, unifi.PreferFixedIP:
+		displayOptions.IP = displayIP
+	default:
+		cobra.CheckErr(fmt.Errorf("unsupported display IP %q (one of %s, %s)", displayIP, unifi.PreferLeasedIP, unifi.PreferFixedIP))
+	}
+}
+
+// initDisplayTime applies the --timezone, --absolute-time, and
+// --time-format flags.
+func initDisplayTime() {
+	if absoluteTime {
+		displayOptions.TimeLayout = timeFormat
+	}
+
+	if timezone == "" {
+		return
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		cobra.CheckErr(fmt.Errorf("unsupported timezone %q: %w", timezone, err))
+	}
+
+	displayOptions.TZ = loc
+}
+
+// writeOutput renders data in the selected structured format, or calls
+// table for the default human readable output. With --jq, data is run
+// through the expression instead, whatever the format.
+func writeOutput(cmd *cobra.Command, data any, table func()) {
+	var options []display.Option
+	if rfc3339Times {
+		options = append(options, display.WithRFC3339Times(displayOptions.Location()))
+	}
+
+	if outputQuery != "" {
+		cobra.CheckErr(display.Query(cmd.OutOrStdout(), outputFormat, outputFields, outputQuery, data, options...))
+
+		return
+	}
+
+	if !display.IsStructured(outputFormat) {
+		if !display.IsTable(outputFormat) {
+			cobra.CheckErr(fmt.Errorf("unsupported output format %q (one of %s)",
+				outputFormat, strings.Join(display.Formats, ", ")))
+		}
+
+		table()
+
+		return
+	}
+
+	cobra.CheckErr(display.Write(cmd.OutOrStdout(), outputFormat, outputFields, data, options...))
+}
+
+func init() { // nolint: gochecknoinits
+	cobra.OnInitialize(initDisplayUnits, initDisplayTime, initDisplayIP)
+
+	pf := rootCmd.PersistentFlags()
+
+	pf.StringVarP(&outputFormat, outputFlag, "o", outputFormat,
+		"output format ("+strings.Join(display.Formats, ", ")+")")
+	pf.StringSliceVar(&outputFields, fieldsFlag, outputFields,
+		"comma separated list of fields to include in json/yaml output (e.g. name,ip,mac)")
+	pf.StringVar(&outputQuery, jqFlag, outputQuery,
+		`jq expression to apply to the json output, e.g. '.[] | select(.signal < -70) | .name'`)
+	pf.StringVar(&sizeUnits, unitsFlag, sizeUnits, "prefixes for sizes: si (kB, MB) or iec (KiB, MiB)")
+	pf.StringVar(&rateUnits, rateUnitsFlag, rateUnits, "units for rates: bits (Mbps) or bytes (MB/s)")
+	pf.StringVar(&timezone, timezoneFlag, timezone,
+		`time zone for displayed times, e.g. "America/Los_Angeles" or "UTC" (default is the local zone)`)
+	pf.BoolVar(&absoluteTime, absTimeFlag, absoluteTime, `show absolute times instead of relative ones like "2 hours ago"`)
+	pf.StringVar(&timeFormat, timeFmtFlag, timeFormat, "Go time layout for --absolute-time")
+	pf.StringVar(&displayIP, displayIPFlag, displayIP,
+		"client IP to show when a fixed IP differs from the current one: leased or fixed")
+	pf.BoolVar(&rfc3339Times, rfc3339Flag, rfc3339Times,
+		"write timestamps in json/yaml output as RFC 3339 strings instead of epoch numbers")
+}
@@ -2,41 +2,106 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 // rawCmd represents the raw command
 var rawCmd = &cobra.Command{
-	Use:     "raw",
+	Use:     "raw [method] <path> [body]",
 	Aliases: []string{"r"},
 	Short:   "issue raw API commands",
-	Args:    cobra.RangeArgs(1, 2),
+	Long: `Call an API endpoint below the site, e.g. /stat/health, and print the response.
+The method (GET, POST, PUT, or DELETE) may be given before the path or with
+--method, and the body as the last argument or with --data, where @file reads
+it from a file and @- from stdin. With --output json or yaml, or --jq, the
+response is parsed as JSON.`,
+	Example: `raw GET /stat/health
+raw POST /cmd/stamgr --data @kick.json
+raw DELETE /rest/user/<id>
+raw /stat/sta --jq '.data[].hostname'`,
+	Args: cobra.RangeArgs(1, 3),
 	Run: func(cmd *cobra.Command, args []string) {
-		ses, err := initSession(cmd)
-		cobra.CheckErr(err)
+		verb := strings.ToUpper(method)
+
+		switch m := strings.ToUpper(args[0]); {
+		case len(args) > 1 && isHTTPMethod(m):
+			verb, args = m, args[1:]
+		case len(args) > 1 && !strings.HasPrefix(args[0], "/"):
+			verb = args[0]
+		}
+
+		if !isHTTPMethod(verb) {
+			cobra.CheckErr(fmt.Errorf("unsupported method %q (one of GET, POST, PUT, DELETE)", verb))
+		}
 
 		var (
 			path = args[0]
 			body io.Reader
 		)
 
-		if len(args) == 2 {
+		switch {
+		case len(args) == 3:
+			cobra.CheckErr(fmt.Errorf("unknown method %q; expected [method] <path> [body]", args[0]))
+		case len(args) == 2:
 			body = bytes.NewBufferString(args[1])
+		case rawData != "":
+			data, err := readRawData(cmd, rawData)
+			cobra.CheckErr(err)
+
+			body = bytes.NewReader(data)
 		}
 
-		out, err := ses.Raw(method, path, body)
+		ses, err := initSession(cmd)
 		cobra.CheckErr(err)
 
-		cmd.Printf("%s\n", out)
+		out, err := ses.Raw(verb, path, body)
+		cobra.CheckErr(err)
+
+		var parsed any
+		if json.Unmarshal([]byte(out), &parsed) != nil {
+			parsed = out
+		}
+
+		writeOutput(cmd, parsed, func() { cmd.Printf("%s\n", out) })
 	},
 }
 
-var method = http.MethodGet
+// readRawData returns the --data value, or the contents of the named file
+// if it starts with @, or stdin for @-.
+func readRawData(cmd *cobra.Command, data string) ([]byte, error) {
+	switch {
+	case data == "@-":
+		return io.ReadAll(cmd.InOrStdin())
+	case strings.HasPrefix(data, "@"):
+		return os.ReadFile(strings.TrimPrefix(data, "@"))
+	default:
+		return []byte(data), nil
+	}
+}
+
+func isHTTPMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	method  = http.MethodGet
+	rawData string
+)
 
 func init() { // nolint: gochecknoinits
 	rootCmd.AddCommand(rawCmd)
-	rawCmd.Flags().StringVar(&method, "method", method, "http method")
+	rawCmd.Flags().StringVar(&method, "method", method, "http method: GET, POST, PUT, or DELETE")
+	rawCmd.Flags().StringVar(&rawData, "data", rawData, "request body, or @file to read it from a file (@- for stdin)")
 }
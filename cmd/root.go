@@ -2,25 +2,37 @@ package cmd
 
 import (
 	"fmt"
-	"io"
 	"os"
+	"strings"
+	"time"
 
-	"github.com/nats-io/nats.go"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
-	lnats "github.com/johnweldon/unifi-scheduler/pkg/nats"
 	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
 )
 
 var (
 	cfgFile  string
+	profile  string
 	debug    bool
+	quiet    bool
+	timing   bool
 	username string
 	password string
 	endpoint string
 
+	startupRetries      = 0
+	startupRetryWait    = 2 * time.Second
+	maxStartupRetryWait = time.Minute
+
+	maxClockSkew = unifi.DefaultMaxClockSkew
+
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+
 	Version string
 )
 
@@ -30,17 +42,11 @@ var rootCmd = &cobra.Command{
 	Short:   "utility for interacting with unifi",
 }
 
-var versionCmd = &cobra.Command{
-	Use:     "version",
-	Aliases: []string{"ver", "v"},
-	Short:   "application version",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Printf("Version: %s\n", Version)
-	},
-}
-
-func Execute(version string) {
+func Execute(version, commit, date string) {
 	Version = version
+	build = newBuildInfo(version, commit, date)
+	rootCmd.Version = build.String()
+
 	cobra.CheckErr(rootCmd.Execute())
 }
 
@@ -50,7 +56,10 @@ func init() { // nolint: gochecknoinits
 	pf := rootCmd.PersistentFlags()
 
 	pf.StringVar(&cfgFile, "config", "", "config file (default is $HOME/.unifi-scheduler.yaml)")
+	pf.StringVar(&profile, profileFlag, profile, "named block of settings under profiles in the config file, e.g. office")
 	pf.BoolVar(&debug, "debug", debug, "debug output")
+	pf.BoolVarP(&quiet, "quiet", "q", quiet, "suppress informational messages on stderr; errors are still shown")
+	pf.BoolVar(&timing, "timing", timing, "print how long each controller request took (dns, connect, tls, ttfb, total) to stderr")
 
 	pf.StringVar(&username, usernameFlag, username, "unifi username")
 	_ = cobra.MarkFlagRequired(pf, usernameFlag)
@@ -61,7 +70,19 @@ func init() { // nolint: gochecknoinits
 	pf.StringVar(&endpoint, endpointFlag, endpoint, "unifi endpoint")
 	_ = cobra.MarkFlagRequired(pf, endpointFlag)
 
-	rootCmd.AddCommand(versionCmd)
+	pf.IntVar(&startupRetries, "startup-retries", startupRetries,
+		"retry the initial login this many times, e.g. while the controller boots (-1 retries forever)")
+	pf.DurationVar(&startupRetryWait, "startup-retry-wait", startupRetryWait,
+		"wait before the first login retry; doubles on each retry, up to a minute")
+	pf.DurationVar(&maxClockSkew, "max-clock-skew", maxClockSkew,
+		"warn when the local clock differs from the controller's by more than this (negative disables)")
+
+	pf.DurationVar(&dialTimeout, "dial-timeout", dialTimeout,
+		"limit connecting to the controller to this long (0 uses the default)")
+	pf.DurationVar(&tlsHandshakeTimeout, "tls-handshake-timeout", tlsHandshakeTimeout,
+		"limit the TLS handshake to this long (0 uses the default of 10s)")
+	pf.DurationVar(&responseHeaderTimeout, "response-header-timeout", responseHeaderTimeout,
+		"limit waiting for the controller to start responding to this long (0 for no limit); each request is limited to a minute overall")
 }
 
 func initConfig() {
@@ -74,18 +95,95 @@ func initConfig() {
 		viper.AddConfigPath(".")
 		viper.AddConfigPath(home)
 		viper.SetConfigName(".unifi-scheduler")
-		viper.SetEnvPrefix("unifi")
 	}
 
+	// Precedence is flag > environment > config file > default. Every key
+	// maps to an UNIFI_ environment variable with dashes and dots replaced
+	// by underscores, e.g. --nats-op-timeout is UNIFI_NATS_OP_TIMEOUT and
+	// notify.webhook.url is UNIFI_NOTIFY_WEBHOOK_URL.
+	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(envKeyReplacer)
 	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+		infof(rootCmd, "Using config file: %s\n", viper.ConfigFileUsed())
+
+		cobra.CheckErr(expandConfigEnv(viper.GetViper()))
 	}
 
+	if profile == "" {
+		profile = viper.GetString(profileFlag)
+	}
+
+	_, err := applyProfile(viper.GetViper(), profile)
+	cobra.CheckErr(err)
+
 	postInitConfig(rootCmd.Commands())
 }
 
+// expandConfigEnv replaces $VAR and ${VAR} in the string values of the
+// config file read by v with the named environment variables. Unset
+// variables expand to nothing, and $$ is a literal $.
+func expandConfigEnv(v *viper.Viper) error {
+	file := viper.New()
+	file.SetConfigFile(v.ConfigFileUsed())
+
+	if err := file.ReadInConfig(); err != nil {
+		return err
+	}
+
+	expanded, _ := expandEnvValues(file.AllSettings()).(map[string]any)
+
+	return v.MergeConfigMap(expanded)
+}
+
+func expandEnvValues(val any) any {
+	switch val := val.(type) {
+	case string:
+		return os.Expand(val, func(name string) string {
+			if name == "$" {
+				return "$"
+			}
+
+			return os.Getenv(name)
+		})
+	case []any:
+		for ix := range val {
+			val[ix] = expandEnvValues(val[ix])
+		}
+
+		return val
+	case map[string]any:
+		for k := range val {
+			val[k] = expandEnvValues(val[k])
+		}
+
+		return val
+	default:
+		return val
+	}
+}
+
+// applyProfile merges the settings under profiles.<name> in the config file
+// of v over its top level settings, and returns them. Flags and the
+// environment still take precedence.
+func applyProfile(v *viper.Viper, name string) (*viper.Viper, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	if v.ConfigFileUsed() == "" {
+		return nil, fmt.Errorf("profile %q requires a config file", name)
+	}
+
+	sub := v.Sub(profilesKey + "." + name)
+	if sub == nil {
+		return nil, fmt.Errorf("unknown profile %q in %s", name, v.ConfigFileUsed())
+	}
+
+	return sub, v.MergeConfigMap(sub.AllSettings())
+}
+
 func postInitConfig(commands []*cobra.Command) {
 	for _, cmd := range commands {
 		presetRequiredFlags(cmd)
@@ -109,56 +207,71 @@ func presetRequiredFlags(cmd *cobra.Command) {
 	})
 }
 
+var envKeyReplacer = strings.NewReplacer("-", "_", ".", "_")
+
+// envName returns the environment variable that sets key.
+func envName(key string) string {
+	return strings.ToUpper(envPrefix + "_" + envKeyReplacer.Replace(key))
+}
+
 const (
+	envPrefix = "unifi"
+
 	usernameFlag = "username"
 	passwordFlag = "password"
 	endpointFlag = "endpoint"
+	profileFlag  = "profile"
+	profilesKey  = "profiles"
 )
 
-func initSession(cmd *cobra.Command) (*unifi.Session, error) {
-	ses := &unifi.Session{
-		Endpoint: endpoint,
-		Username: username,
-		Password: password,
+// infof prints an informational message to stderr, unless --quiet.
+func infof(cmd *cobra.Command, format string, args ...any) {
+	if quiet {
+		return
 	}
 
-	nc, err := nats.Connect(natsURL)
+	fmt.Fprintf(cmd.ErrOrStderr(), format, args...)
+}
+
+func initSession(cmd *cobra.Command) (*unifi.Session, error) {
+	ses, err := newSessionBuilder(cmd).Build()
 	if err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "error connecting to NATS: %v\n", err)
+		fmt.Fprintf(cmd.ErrOrStderr(), "error initializing: %v\n", err)
 
 		return nil, err
 	}
 
-	outio := io.MultiWriter(&lnats.Logger{
-		Connection:     nc,
-		PublishSubject: "log.info",
-	}, cmd.OutOrStdout())
-
-	errio := io.MultiWriter(&lnats.Logger{
-		Connection:     nc,
-		PublishSubject: "log.error",
-	}, cmd.ErrOrStderr())
+	return login(cmd, ses)
+}
 
-	options := []unifi.Option{
-		unifi.WithOut(outio),
-		unifi.WithErr(errio),
-	}
+// login authenticates ses, retrying up to --startup-retries times (forever
+// if negative) with a doubling wait, so that a controller which is still
+// booting is waited for. Session errors are sticky, so each retry uses a
+// fresh Clone.
+func login(cmd *cobra.Command, ses *unifi.Session) (*unifi.Session, error) {
+	wait := startupRetryWait
+
+	for attempt := 0; ; attempt++ {
+		msg, err := ses.Login()
+		if err == nil {
+			return ses, nil
+		}
 
-	if debug {
-		options = append(options, unifi.WithDbg(cmd.OutOrStderr()))
-	}
+		if startupRetries >= 0 && attempt >= startupRetries {
+			fmt.Fprintf(cmd.ErrOrStderr(), "error logging in %q: %v\n", msg, err)
 
-	if err := ses.Initialize(options...); err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "error initializing: %v\n", err)
+			return nil, err
+		}
 
-		return nil, err
-	}
+		infof(cmd, "error logging in: %v; retrying in %s\n", err, wait)
 
-	if msg, err := ses.Login(); err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "error logging in %q: %v\n", msg, err)
+		select {
+		case <-cmd.Context().Done():
+			return nil, cmd.Context().Err()
+		case <-time.After(wait):
+		}
 
-		return nil, err
+		wait = min(wait*2, maxStartupRetryWait)
+		ses = ses.Clone()
 	}
-
-	return ses, nil
 }
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/schema"
+)
+
+var schemaTypes = map[string]any{
+	"client": unifi.Client{},
+	"device": unifi.Device{},
+	"event":  unifi.Event{},
+	"guest":  unifi.GuestAuthorization{},
+	"health": unifi.SubsystemHealth{},
+}
+
+var schemaCmd = &cobra.Command{
+	Use:       "schema <type>",
+	Short:     "print the JSON schema of the --output json types",
+	Long:      "print the JSON schema of the --output json types (" + strings.Join(schemaNames(), ", ") + ")",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: schemaNames(),
+	Run: func(cmd *cobra.Command, args []string) {
+		v, ok := schemaTypes[strings.ToLower(args[0])]
+		if !ok {
+			cobra.CheckErr(fmt.Errorf("unknown type %q (one of %s)", args[0], strings.Join(schemaNames(), ", ")))
+		}
+
+		data, err := json.MarshalIndent(schema.For(v), "", "  ")
+		cobra.CheckErr(err)
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+	},
+}
+
+func schemaNames() []string {
+	var names []string
+	for name := range schemaTypes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func init() { // nolint: gochecknoinits
+	rootCmd.AddCommand(schemaCmd)
+}
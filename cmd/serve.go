@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/api"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "serve a JSON HTTP API for clients, devices, events, and blocking",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		opts := []api.Opt{api.OptNameCacheTTL(serveNameCacheTTL)}
+		if serveUsername != "" || servePassword != "" {
+			opts = append(opts, api.OptBasicAuth(serveUsername, servePassword))
+		}
+
+		srv := &http.Server{ // nolint:exhaustivestruct
+			Addr:              serveAddr,
+			Handler:           api.NewServer(ses, opts...),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			<-ctx.Done()
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+
+		infof(cmd, "listening on %s\n", serveAddr)
+
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			cobra.CheckErr(err)
+		}
+	},
+}
+
+var (
+	serveAddr     = "127.0.0.1:8080"
+	serveUsername = ""
+	servePassword = ""
+
+	serveNameCacheTTL = unifi.DefaultNameCacheTTL
+)
+
+func init() { // nolint: gochecknoinits
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", serveAddr, "address to listen on")
+	serveCmd.Flags().StringVar(&serveUsername, "basic-auth-user", serveUsername, "require HTTP basic auth with this username")
+	serveCmd.Flags().StringVar(&servePassword, "basic-auth-password", servePassword, "require HTTP basic auth with this password")
+	serveCmd.Flags().DurationVar(&serveNameCacheTTL, "name-cache-ttl", serveNameCacheTTL,
+		"resolve client names from a cache refreshed at this interval (0 resolves on every request)")
+}
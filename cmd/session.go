@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+
+	lnats "github.com/johnweldon/unifi-scheduler/pkg/nats"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+// SessionBuilder maps the parsed flags to a configured Session.
+type SessionBuilder struct {
+	Endpoint string
+	Username string
+	Password string
+	NATSURL  string
+	Debug    bool
+	Quiet    bool
+
+	MaxClockSkew    time.Duration
+	SkipIfSatisfied bool
+	Timing          bool
+
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+
+	Out io.Writer
+	Err io.Writer
+	Dbg io.Writer
+
+	// Connect is used to reach NATS when NATSURL is set; it defaults to
+	// nats.Connect with reconnect options.
+	Connect func(url string) (*nats.Conn, error)
+}
+
+// newSessionBuilder returns a SessionBuilder for the global flags, writing
+// to the output streams of cmd.
+func newSessionBuilder(cmd *cobra.Command) SessionBuilder {
+	return SessionBuilder{
+		Endpoint: endpoint,
+		Username: username,
+		Password: password,
+		NATSURL:  natsURL,
+		Debug:    debug,
+		Quiet:    quiet,
+
+		MaxClockSkew:    maxClockSkew,
+		SkipIfSatisfied: skipIfSatisfied,
+		Timing:          timing,
+
+		DialTimeout:           dialTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ResponseHeaderTimeout: responseHeaderTimeout,
+
+		Out: cmd.OutOrStdout(),
+		Err: cmd.ErrOrStderr(),
+		Dbg: cmd.OutOrStderr(),
+	}
+}
+
+// Options returns the Session options for the builder. Logs are mirrored
+// to NATS unless NATSURL is empty.
+func (b SessionBuilder) Options() ([]unifi.Option, error) {
+	outio, errio := b.Out, b.Err
+
+	if b.NATSURL != "" {
+		connect := b.Connect
+		if connect == nil {
+			connect = func(url string) (*nats.Conn, error) { return nats.Connect(url, lnats.ReconnectOptions()...) }
+		}
+
+		nc, err := connect(b.NATSURL)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to NATS: %w", err)
+		}
+
+		outio = io.MultiWriter(&lnats.Logger{
+			Connection:     nc,
+			PublishSubject: "log.info",
+		}, outio)
+
+		errio = io.MultiWriter(&lnats.Logger{
+			Connection:     nc,
+			PublishSubject: "log.error",
+		}, errio)
+	}
+
+	options := []unifi.Option{
+		unifi.WithOut(outio),
+		unifi.WithErr(errio),
+		unifi.WithMaxClockSkew(b.MaxClockSkew),
+		unifi.WithSkipIfSatisfied(b.SkipIfSatisfied),
+		unifi.WithDialTimeout(b.DialTimeout),
+		unifi.WithTLSHandshakeTimeout(b.TLSHandshakeTimeout),
+		unifi.WithResponseHeaderTimeout(b.ResponseHeaderTimeout),
+	}
+
+	if b.Debug {
+		options = append(options, unifi.WithDbg(b.Dbg))
+	}
+
+	if b.Quiet {
+		options = append(options, unifi.WithInfo(io.Discard))
+	}
+
+	// Timing goes to stderr so it doesn't mix with structured output.
+	if b.Timing {
+		options = append(options, unifi.WithTiming(b.Err))
+	}
+
+	return options, nil
+}
+
+// Build returns an initialized Session that has not yet logged in.
+func (b SessionBuilder) Build() (*unifi.Session, error) {
+	options, err := b.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	ses := &unifi.Session{
+		Endpoint: b.Endpoint,
+		Username: b.Username,
+		Password: b.Password,
+	}
+
+	if err = ses.Initialize(options...); err != nil {
+		return nil, err
+	}
+
+	return ses, nil
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+)
+
+func TestNewSessionBuilder(t *testing.T) {
+	restore(t, &endpoint, &username, &password, &natsURL)
+	restore(t, &debug, &quiet, &skipIfSatisfied, &timing)
+	restore(t, &maxClockSkew, &dialTimeout, &tlsHandshakeTimeout, &responseHeaderTimeout)
+
+	endpoint, username, password, natsURL = "https://192.168.1.1", "admin", "secret", "nats://localhost:4222"
+	debug, quiet, skipIfSatisfied, timing = true, true, true, true
+	maxClockSkew, dialTimeout, tlsHandshakeTimeout, responseHeaderTimeout = time.Minute, 2*time.Second, 3*time.Second, 4*time.Second
+
+	var out, errOut bytes.Buffer
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+
+	b := newSessionBuilder(cmd)
+
+	want := SessionBuilder{
+		Endpoint: "https://192.168.1.1", Username: "admin", Password: "secret", NATSURL: "nats://localhost:4222",
+		Debug: true, Quiet: true, MaxClockSkew: time.Minute, SkipIfSatisfied: true, Timing: true,
+		DialTimeout: 2 * time.Second, TLSHandshakeTimeout: 3 * time.Second, ResponseHeaderTimeout: 4 * time.Second,
+	}
+
+	got := b
+	got.Out, got.Err, got.Dbg = nil, nil, nil
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if b.Out != &out || b.Err != &errOut {
+		t.Errorf("output streams are not the command's")
+	}
+}
+
+// restore resets the flag variables to their current values when the test
+// ends.
+func restore[T any](t *testing.T, vars ...*T) {
+	for _, v := range vars {
+		saved := *v
+
+		t.Cleanup(func() { *v = saved })
+	}
+}
+
+func TestSessionBuilderOptions(t *testing.T) {
+	base, err := SessionBuilder{}.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		b     SessionBuilder
+		extra int
+	}{
+		{"defaults", SessionBuilder{}, 0},
+		{"timeouts", SessionBuilder{DialTimeout: time.Second, TLSHandshakeTimeout: time.Second, ResponseHeaderTimeout: time.Second}, 0},
+		{"debug", SessionBuilder{Debug: true}, 1},
+		{"quiet", SessionBuilder{Quiet: true}, 1},
+		{"timing", SessionBuilder{Timing: true}, 1},
+		{"all", SessionBuilder{Debug: true, Quiet: true, Timing: true}, 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := tc.b.Options()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := len(options), len(base)+tc.extra; got != want {
+				t.Errorf("got %d options, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestSessionBuilderOptionsNATS(t *testing.T) {
+	var dialed string
+
+	b := SessionBuilder{
+		NATSURL: "nats://example:4222",
+		Connect: func(url string) (*nats.Conn, error) {
+			dialed = url
+
+			return nil, errors.New("refused")
+		},
+	}
+
+	_, err := b.Options()
+	if err == nil || !strings.Contains(err.Error(), "connecting to NATS: refused") {
+		t.Errorf("got error %v, want the connect error", err)
+	}
+
+	if dialed != b.NATSURL {
+		t.Errorf("connected to %q, want %q", dialed, b.NATSURL)
+	}
+}
+
+func TestSessionBuilderBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        SessionBuilder
+		endpoint string
+		err      string
+	}{
+		{"complete", SessionBuilder{Endpoint: "192.168.1.1/", Username: "admin", Password: "secret"}, "https://192.168.1.1", ""},
+		{"no endpoint", SessionBuilder{Username: "admin", Password: "secret"}, "", "missing endpoint"},
+		{"plain http", SessionBuilder{Endpoint: "http://192.168.1.1", Username: "admin", Password: "secret"}, "", "http"},
+		{"no username", SessionBuilder{Endpoint: "192.168.1.1", Password: "secret"}, "", "missing username"},
+		{"no password", SessionBuilder{Endpoint: "192.168.1.1", Username: "admin"}, "", "missing password"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ses, err := tc.b.Build()
+
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("got error %v, want one containing %q", err, tc.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ses.Endpoint != tc.endpoint {
+				t.Errorf("got endpoint %q, want %q", ses.Endpoint, tc.endpoint)
+			}
+		})
+	}
+}
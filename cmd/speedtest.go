@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var speedTestStatusOnly bool
+
+var speedTestCmd = &cobra.Command{
+	Use:     "speedtest",
+	Aliases: []string{"speed", "st"},
+	Short:   "run a WAN speed test",
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		if speedTestStatusOnly {
+			result, err := ses.GetSpeedTestStatus()
+			cobra.CheckErr(err)
+
+			writeOutput(cmd, result, func() { cmd.Printf("%s\n", result.Display(displayOptions)) })
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), speedTestTimeout)
+		defer cancel()
+
+		result, err := ses.RunSpeedTest(ctx)
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, result, func() { cmd.Printf("%s\n", result.Display(displayOptions)) })
+	},
+}
+
+var speedTestTimeout = 3 * time.Minute
+
+func init() { // nolint: gochecknoinits
+	rootCmd.AddCommand(speedTestCmd)
+
+	speedTestCmd.Flags().BoolVar(&speedTestStatusOnly, "status", speedTestStatusOnly, "show the last result without running a new test")
+	speedTestCmd.Flags().DurationVar(&speedTestTimeout, "timeout", speedTestTimeout, "maximum time to wait for the result")
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:     "stats",
+	Aliases: []string{"stat"},
+	Short:   "summarize the network in aggregate",
+}
+
+func init() { // nolint: gochecknoinits
+	rootCmd.AddCommand(statsCmd)
+}
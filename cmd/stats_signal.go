@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+var statsSignalCmd = &cobra.Command{
+	Use:     "signal",
+	Aliases: []string{"sig"},
+	Short:   "count connected wireless clients by signal strength and band",
+	Long: `Bucket the connected wireless clients by signal strength: excellent (above
+-50 dBm), good (-67 to -50), fair (-75 to -67), and poor (below -75), with the
+count for each band.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		dist, err := ses.GetSignalDistribution()
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, dist, func() {
+			t := table.NewWriter()
+			t.SetStyle(display.StyleDefault)
+			t.SetOutputMirror(cmd.OutOrStdout())
+
+			header := table.Row{"Signal", "Range"}
+			for _, band := range dist.Bands {
+				header = append(header, band)
+			}
+
+			t.AppendHeader(append(header, "Clients"))
+
+			for _, bucket := range dist.Buckets {
+				row := table.Row{bucket.Quality, bucket.Range}
+				for _, band := range dist.Bands {
+					row = append(row, bucket.Bands[band])
+				}
+
+				t.AppendRow(append(row, bucket.Count))
+			}
+
+			footer := table.Row{"Total", ""}
+			for range dist.Bands {
+				footer = append(footer, "")
+			}
+
+			t.AppendFooter(append(footer, dist.Clients))
+			t.Render()
+		})
+	},
+}
+
+func init() { // nolint: gochecknoinits
+	statsCmd.AddCommand(statsSignalCmd)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var tlsCmd = &cobra.Command{
+	Use:   "tls",
+	Short: "TLS tools",
+	// The TLS commands only connect to the endpoint, so the credentials
+	// are not required.
+	PersistentPreRun: credentialsOptional,
+}
+
+// endpointAddr returns the host:port of an endpoint URL, defaulting to
+// https on port 443.
+func endpointAddr(endpoint string) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("invalid endpoint %q: missing host", endpoint)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+func init() { // nolint: gochecknoinits
+	rootCmd.AddCommand(tlsCmd)
+}
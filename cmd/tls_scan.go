@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/tlsscan"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/display"
+)
+
+var tlsScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "report the TLS versions and cipher suites the endpoint accepts",
+	Long: `Attempt a handshake with the endpoint for each TLS version, and for TLS 1.2
+and earlier with each cipher suite, and report which are accepted. Useful
+before restricting the TLS versions or ciphers used to reach the controller.
+Certificates are not verified while scanning.`,
+	Example: "tls scan --endpoint https://192.168.1.1",
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, err := endpointAddr(endpoint)
+		cobra.CheckErr(err)
+
+		infof(cmd, "scanning %s\n", addr)
+
+		results, err := tlsscan.Scan(cmd.Context(), addr, tlsScanTimeout)
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, results, func() {
+			t := table.NewWriter()
+			t.SetStyle(display.StyleDefault)
+			t.SetOutputMirror(cmd.OutOrStdout())
+			t.SetTitle(addr)
+			t.AppendHeader(table.Row{"Version", "Supported", "Ciphers"})
+
+			for _, r := range results {
+				t.AppendRow(table.Row{r.Version, r.Supported, strings.Join(r.Ciphers, "\n")})
+				t.AppendSeparator()
+			}
+
+			t.Render()
+		})
+	},
+}
+
+var tlsScanTimeout = 5 * time.Second
+
+func init() { // nolint: gochecknoinits
+	tlsScanCmd.Flags().DurationVar(&tlsScanTimeout, "timeout", tlsScanTimeout, "timeout for each handshake")
+
+	tlsCmd.AddCommand(tlsScanCmd)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+var topologyCmd = &cobra.Command{
+	Use:     "topology [device]",
+	Aliases: []string{"topo", "tree"},
+	Short:   "show the network as a tree from the gateway down to the clients",
+	Long: `Show the devices arranged by their uplinks, gateway first, with each
+device's downstream devices and clients indented below it and the port they
+are connected to. Pass a device name or MAC to show only that device and
+everything downstream of it.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		root, err := ses.GetTopology()
+		cobra.CheckErr(err)
+
+		if len(args) > 0 {
+			if root = root.Find(args[0]); root == nil {
+				cobra.CheckErr(fmt.Errorf("no device or client %q in the topology", args[0]))
+			}
+		}
+
+		if !topologyClients {
+			root.Walk(func(node *unifi.TopologyNode, _ int) {
+				devices := node.Children[:0]
+
+				for _, child := range node.Children {
+					if child.Kind != unifi.TopologyClient {
+						devices = append(devices, child)
+					}
+				}
+
+				node.Children = devices
+			})
+		}
+
+		writeOutput(cmd, root, func() {
+			root.Walk(func(node *unifi.TopologyNode, depth int) {
+				cmd.Printf("%s%s\n", strings.Repeat("  ", depth), node.String())
+			})
+		})
+	},
+}
+
+var topologyClients = true
+
+func init() { // nolint: gochecknoinits
+	topologyCmd.Flags().BoolVar(&topologyClients, "clients", topologyClients, "include connected clients")
+
+	rootCmd.AddCommand(topologyCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+var userGroupsCmd = &cobra.Command{
+	Use:     "groups",
+	Aliases: []string{"usergroups"},
+	Short:   "list user groups (bandwidth profiles)",
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		groups, err := ses.GetUserGroups()
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, groups, func() {
+			for _, group := range groups {
+				cmd.Printf("%s\n", group.String())
+			}
+		})
+	},
+}
+
+var setUserGroupCmd = &cobra.Command{
+	Use:     "set-group <mac> <group-name-or-id>",
+	Short:   "move a client to a user group",
+	Example: "user set-group aa:bb:cc:dd:ee:ff limited",
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		hw, err := net.ParseMAC(args[0])
+		cobra.CheckErr(err)
+
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		groups, err := ses.GetUserGroups()
+		cobra.CheckErr(err)
+
+		groupID := findUserGroup(groups, args[1])
+		if len(groupID) == 0 {
+			cobra.CheckErr(fmt.Errorf("unknown user group %q", args[1]))
+		}
+
+		_, err = ses.SetClientUserGroup(unifi.MAC(hw.String()), groupID)
+		cobra.CheckErr(err)
+
+		infof(cmd, "moved %s to %s\n", hw, args[1])
+	},
+}
+
+func findUserGroup(groups []unifi.UserGroup, nameOrID string) string {
+	for _, group := range groups {
+		if group.ID == nameOrID || group.Name == nameOrID {
+			return group.ID
+		}
+	}
+
+	return ""
+}
+
+func init() { // nolint: gochecknoinits
+	userCmd.AddCommand(userGroupsCmd)
+	userCmd.AddCommand(setUserGroupCmd)
+}
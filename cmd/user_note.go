@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"net"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+var noteUserCmd = &cobra.Command{
+	Use:     "note <mac> [note]",
+	Short:   "set or clear the note on a client",
+	Long:    "Set the note on a client, or clear it when the note is empty or omitted.",
+	Example: `user note aa:bb:cc:dd:ee:ff "owner: sam, office"`,
+	Args:    cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		hw, err := net.ParseMAC(args[0])
+		cobra.CheckErr(err)
+
+		note := ""
+		if len(args) > 1 {
+			note = strings.TrimSpace(args[1])
+		}
+
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		_, err = ses.SetClientNote(unifi.MAC(hw.String()), note)
+		cobra.CheckErr(err)
+
+		if note == "" {
+			infof(cmd, "cleared note on %s\n", hw)
+		} else {
+			infof(cmd, "set note on %s\n", hw)
+		}
+	},
+}
+
+func init() { // nolint: gochecknoinits
+	userCmd.AddCommand(noteUserCmd)
+}
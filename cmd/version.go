@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	rtdebug "runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// BuildInfo describes the running binary.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+func (b BuildInfo) String() string {
+	s := b.Version
+
+	if b.Commit != "" {
+		s += " (" + b.Commit
+
+		if b.Date != "" {
+			s += ", " + b.Date
+		}
+
+		s += ")"
+	}
+
+	return fmt.Sprintf("%s %s %s", s, b.GoVersion, b.Platform)
+}
+
+var build BuildInfo
+
+// newBuildInfo uses the values set at build time with -ldflags, falling
+// back to those recorded by the Go toolchain, e.g. for go install.
+func newBuildInfo(version, commit, date string) BuildInfo {
+	b := BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	info, ok := rtdebug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+
+	if (b.Version == "" || b.Version == "dev") && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		b.Version = info.Main.Version
+	}
+
+	modified := false
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if b.Commit == "" {
+				b.Commit = setting.Value
+			}
+		case "vcs.time":
+			if b.Date == "" {
+				b.Date = setting.Value
+			}
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+
+	if modified && commit == "" && b.Commit != "" {
+		b.Commit += "-dirty"
+	}
+
+	return b
+}
+
+var versionCmd = &cobra.Command{
+	Use:     "version",
+	Aliases: []string{"ver", "v"},
+	Short:   "application version and build details",
+	// Printing the version doesn't talk to the controller.
+	PersistentPreRun: credentialsOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		writeOutput(cmd, build, func() {
+			cmd.Printf("Version: %s\n", build.Version)
+			cmd.Printf("Commit:  %s\n", build.Commit)
+			cmd.Printf("Built:   %s\n", build.Date)
+			cmd.Printf("Go:      %s %s\n", build.GoVersion, build.Platform)
+		})
+	},
+}
+
+func init() { // nolint: gochecknoinits
+	rootCmd.SetVersionTemplate("{{ .Version }}\n")
+	rootCmd.AddCommand(versionCmd)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+var wlanCmd = &cobra.Command{
+	Use:     "wlan",
+	Aliases: []string{"ssid", "wifi"},
+	Short:   "interact with wireless network configuration",
+}
+
+var wlanListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "list wireless networks",
+	Run: func(cmd *cobra.Command, args []string) {
+		ses, err := initSession(cmd)
+		cobra.CheckErr(err)
+
+		wlans, err := ses.GetWLANs()
+		cobra.CheckErr(err)
+
+		writeOutput(cmd, wlans, func() {
+			for _, wlan := range wlans {
+				cmd.Printf("%s\n", wlan.String())
+			}
+		})
+	},
+}
+
+var wlanEnableCmd = &cobra.Command{
+	Use:     "enable",
+	Short:   "enable a wireless network",
+	Example: "<ssid-or-id>",
+	Args:    cobra.ExactArgs(1),
+	Run:     func(cmd *cobra.Command, args []string) { setWLANEnabled(cmd, args[0], true) },
+}
+
+var wlanDisableCmd = &cobra.Command{
+	Use:     "disable",
+	Short:   "disable a wireless network",
+	Example: "<ssid-or-id>",
+	Args:    cobra.ExactArgs(1),
+	Run:     func(cmd *cobra.Command, args []string) { setWLANEnabled(cmd, args[0], false) },
+}
+
+func setWLANEnabled(cmd *cobra.Command, nameOrID string, enabled bool) {
+	ses, err := initSession(cmd)
+	cobra.CheckErr(err)
+
+	wlans, err := ses.GetWLANs()
+	cobra.CheckErr(err)
+
+	id := findWLAN(wlans, nameOrID)
+	if len(id) == 0 {
+		cobra.CheckErr(fmt.Errorf("unknown wlan %q", nameOrID))
+	}
+
+	out, err := ses.SetWLANEnabled(id, enabled)
+	cobra.CheckErr(err)
+
+	cmd.Printf("%s\n", out)
+}
+
+func findWLAN(wlans []unifi.WLAN, nameOrID string) string {
+	for _, wlan := range wlans {
+		if wlan.ID == nameOrID || wlan.Name == nameOrID {
+			return wlan.ID
+		}
+	}
+
+	return ""
+}
+
+func init() { // nolint: gochecknoinits
+	wlanCmd.AddCommand(wlanListCmd)
+	wlanCmd.AddCommand(wlanEnableCmd)
+	wlanCmd.AddCommand(wlanDisableCmd)
+
+	rootCmd.AddCommand(wlanCmd)
+}
@@ -2,8 +2,14 @@ package main
 
 import "github.com/johnweldon/unifi-scheduler/cmd"
 
-var version = "dev"
+// Set at build time with -ldflags "-X main.version=... -X main.commit=... -X main.date=...",
+// as goreleaser does by default.
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
 
 func main() {
-	cmd.Execute(version)
+	cmd.Execute(version, commit, date)
 }
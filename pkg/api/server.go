@@ -0,0 +1,247 @@
+// Package api exposes a UniFi Session as a small JSON HTTP API.
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+type Opt func(*Server)
+
+// OptBasicAuth requires HTTP basic auth with the given credentials.
+func OptBasicAuth(username, password string) Opt {
+	return func(s *Server) { s.username, s.password = username, password }
+}
+
+// OptNameCacheTTL resolves client names from a copy of the controller's
+// names that is refreshed at most once per ttl, rather than on every
+// request.
+func OptNameCacheTTL(ttl time.Duration) Opt {
+	return func(s *Server) { s.resolver = unifi.NewNameResolver(s.session, ttl) }
+}
+
+// Server serves:
+//
+//	GET  /clients   connected clients (?all=true for all known clients)
+//	GET  /devices   devices
+//	GET  /events    recent events (?all=true for all events)
+//	POST /block     block clients, body {"macs": [...], "names": [...]}
+//	POST /unblock   unblock clients, same body as /block
+//	GET  /stats     request counts of the session and the name cache
+//
+// A Session is not safe for concurrent use, so requests are serialized.
+// A session stopped by an error, such as a dropped connection, logs in
+// again on the next request rather than failing every request after it.
+type Server struct {
+	mu       sync.Mutex
+	session  *unifi.Session
+	resolver *unifi.NameResolver
+
+	username string
+	password string
+
+	mux *http.ServeMux
+}
+
+func NewServer(s *unifi.Session, opts ...Opt) *Server {
+	srv := &Server{session: s, resolver: unifi.NewNameResolver(s, 0), mux: http.NewServeMux()}
+
+	for _, opt := range opts {
+		opt(srv)
+	}
+
+	srv.mux.HandleFunc("GET /clients", srv.clients)
+	srv.mux.HandleFunc("GET /devices", srv.devices)
+	srv.mux.HandleFunc("GET /events", srv.events)
+	srv.mux.HandleFunc("GET /stats", srv.stats)
+	srv.mux.HandleFunc("POST /block", srv.macsAction(func(s *unifi.Session) func(...unifi.MAC) (string, error) { return s.Block }))
+	srv.mux.HandleFunc("POST /unblock", srv.macsAction(func(s *unifi.Session) func(...unifi.MAC) (string, error) { return s.Unblock }))
+
+	return srv
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.username != "" || s.password != "" {
+		u, p, ok := r.BasicAuth()
+		if !ok || !equal(u, s.username) || !equal(p, s.password) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="unifi-scheduler"`)
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("unauthorized"))
+
+			return
+		}
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// revive logs the session in again if an earlier error stopped it.
+// Callers must hold mu.
+func (s *Server) revive(w http.ResponseWriter) bool {
+	if s.session.Err() == nil {
+		return true
+	}
+
+	if _, err := s.session.Relogin(); err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("logging in again: %w", err))
+
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) clients(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.revive(w) {
+		return
+	}
+
+	fetch := s.session.GetClients
+	if r.URL.Query().Get("all") == "true" {
+		fetch = s.session.GetAllClients
+	}
+
+	clients, err := fetch()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("getting clients: %w", err))
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, clients)
+}
+
+func (s *Server) devices(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.revive(w) {
+		return
+	}
+
+	devices, err := s.session.GetDevices()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("getting devices: %w", err))
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, devices)
+}
+
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.revive(w) {
+		return
+	}
+
+	fetch := s.session.GetRecentEvents
+	if r.URL.Query().Get("all") == "true" {
+		fetch = s.session.GetAllEvents
+	}
+
+	events, err := fetch()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("getting events: %w", err))
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, events)
+}
+
+// StatsResponse is the body of the stats endpoint.
+type StatsResponse struct {
+	Session   unifi.SessionStats `json:"session"`
+	NameCache unifi.CacheStats   `json:"name_cache"`
+}
+
+func (s *Server) stats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, StatsResponse{Session: s.session.Stats(), NameCache: s.resolver.Stats()})
+}
+
+// MACsRequest is the body of the block and unblock endpoints. Names are
+// resolved to MACs the same way the CLI resolves its arguments.
+type MACsRequest struct {
+	MACs  []unifi.MAC `json:"macs,omitempty"`
+	Names []string    `json:"names,omitempty"`
+}
+
+// MACsResponse lists the MACs an action was applied to.
+type MACsResponse struct {
+	MACs []unifi.MAC `json:"macs"`
+}
+
+func (s *Server) macsAction(action func(*unifi.Session) func(...unifi.MAC) (string, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req MACsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("decoding request: %w", err))
+
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.revive(w) {
+			return
+		}
+
+		macs := req.MACs
+
+		if len(req.Names) > 0 {
+			named, err := s.resolver.GetMACsBy(req.Names...)
+			if err != nil {
+				writeError(w, http.StatusBadGateway, fmt.Errorf("resolving names: %w", err))
+
+				return
+			}
+
+			macs = append(macs, named...)
+		}
+
+		if len(macs) == 0 {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no matching clients"))
+
+			return
+		}
+
+		if _, err := action(s.session)(macs...); err != nil {
+			writeError(w, http.StatusBadGateway, err)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, MACsResponse{MACs: macs})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("api: writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func equal(a, b string) bool { return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1 }
@@ -0,0 +1,193 @@
+// Package exporter serves per-client and per-device gauges in the
+// Prometheus text exposition format.
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+// DefaultCacheTTL is how long fetched clients and devices are reused
+// across scrapes.
+var DefaultCacheTTL = 30 * time.Second
+
+type Opt func(*Exporter)
+
+// OptCacheTTL sets how long fetched data is reused across scrapes.
+func OptCacheTTL(ttl time.Duration) Opt { return func(e *Exporter) { e.ttl = ttl } }
+
+// Exporter fetches clients and devices on scrape, reusing the previous
+// fetch for the cache TTL so frequent scrapes don't load the controller.
+type Exporter struct {
+	mu      sync.Mutex
+	session *unifi.Session
+	ttl     time.Duration
+
+	fetched time.Time
+	clients []unifi.Client
+	devices []unifi.Device
+	err     error
+}
+
+func New(s *unifi.Session, opts ...Opt) *Exporter {
+	e := &Exporter{session: s, ttl: DefaultCacheTTL}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	clients, devices, err := e.fetch()
+	if err != nil {
+		log.Printf("exporter: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	if err = Write(w, clients, devices, err == nil); err != nil {
+		log.Printf("exporter: writing metrics: %v", err)
+	}
+}
+
+func (e *Exporter) fetch() ([]unifi.Client, []unifi.Device, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.fetched.IsZero() && time.Since(e.fetched) < e.ttl {
+		return e.clients, e.devices, e.err
+	}
+
+	e.fetched = time.Now()
+
+	// A session stopped by an error fails every call after it; log in
+	// again so one failed scrape doesn't leave unifi_up at 0.
+	if e.session.Err() != nil {
+		if _, e.err = e.session.Relogin(); e.err != nil {
+			e.err = fmt.Errorf("logging in again: %w", e.err)
+
+			return nil, nil, e.err
+		}
+	}
+
+	if e.devices, e.err = e.session.GetDevices(); e.err != nil {
+		e.err = fmt.Errorf("getting devices: %w", e.err)
+
+		return nil, nil, e.err
+	}
+
+	if e.clients, e.err = e.session.GetClients(); e.err != nil {
+		e.err = fmt.Errorf("getting clients: %w", e.err)
+
+		return nil, nil, e.err
+	}
+
+	return e.clients, e.devices, nil
+}
+
+type metric struct {
+	name string
+	help string
+}
+
+var (
+	clientSignal       = metric{"unifi_client_signal_dbm", "Wireless signal strength of the client."}
+	clientReceived     = metric{"unifi_client_received_bytes", "Bytes received by the client."}
+	clientSent         = metric{"unifi_client_sent_bytes", "Bytes sent by the client."}
+	clientUptime       = metric{"unifi_client_uptime_seconds", "Seconds the client has been connected."}
+	clientSatisfaction = metric{"unifi_client_satisfaction", "Client satisfaction score, 0 to 100."}
+
+	deviceReceived     = metric{"unifi_device_received_bytes", "Bytes received by the device."}
+	deviceSent         = metric{"unifi_device_sent_bytes", "Bytes sent by the device."}
+	deviceUptime       = metric{"unifi_device_uptime_seconds", "Seconds since the device started."}
+	deviceSatisfaction = metric{"unifi_device_satisfaction", "Device satisfaction score, 0 to 100."}
+	deviceClients      = metric{"unifi_device_clients", "Clients connected to the device."}
+
+	up = metric{"unifi_up", "Whether the last fetch from the controller succeeded."}
+)
+
+// Write renders clients and devices as gauges. Clients are labeled by mac,
+// name, and ap (the upstream device); devices by mac, name, and model.
+func Write(out io.Writer, clients []unifi.Client, devices []unifi.Device, ok bool) error {
+	w := bufio.NewWriter(out)
+
+	gauge := func(m metric, rows func(func(labels []string, value int64))) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name)
+		rows(func(labels []string, value int64) {
+			fmt.Fprintf(w, "%s{%s} %d\n", m.name, formatLabels(labels), value)
+		})
+	}
+
+	eachClient := func(value func(unifi.Client) (int64, bool)) func(func([]string, int64)) {
+		return func(emit func([]string, int64)) {
+			for _, c := range clients {
+				if v, ok := value(c); ok {
+					emit([]string{"mac", c.MAC.String(), "name", c.DisplayName(), "ap", c.DisplaySwitchName()}, v)
+				}
+			}
+		}
+	}
+
+	eachDevice := func(value func(unifi.Device) int64) func(func([]string, int64)) {
+		return func(emit func([]string, int64)) {
+			for _, d := range devices {
+				emit([]string{"mac", d.MAC.String(), "name", d.Name, "model", d.Model}, value(d))
+			}
+		}
+	}
+
+	upValue := int64(0)
+	if ok {
+		upValue = 1
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", up.name, up.help, up.name, up.name, upValue)
+
+	gauge(clientSignal, eachClient(func(c unifi.Client) (int64, bool) { return c.Signal, !c.IsWired }))
+	gauge(clientReceived, eachClient(func(c unifi.Client) (int64, bool) {
+		if c.IsWired {
+			return c.WiredBytesReceived, true
+		}
+
+		return c.BytesReceived, true
+	}))
+	gauge(clientSent, eachClient(func(c unifi.Client) (int64, bool) {
+		if c.IsWired {
+			return c.WiredBytesSent, true
+		}
+
+		return c.BytesSent, true
+	}))
+	gauge(clientUptime, eachClient(func(c unifi.Client) (int64, bool) { return c.Uptime, true }))
+	gauge(clientSatisfaction, eachClient(func(c unifi.Client) (int64, bool) { return c.Satisfaction, !c.IsWired }))
+
+	gauge(deviceReceived, eachDevice(func(d unifi.Device) int64 { return d.BytesReceived }))
+	gauge(deviceSent, eachDevice(func(d unifi.Device) int64 { return d.BytesSent }))
+	gauge(deviceUptime, eachDevice(func(d unifi.Device) int64 { return int64(d.Uptime) }))
+	gauge(deviceSatisfaction, eachDevice(func(d unifi.Device) int64 { return d.Satisfaction }))
+	gauge(deviceClients, eachDevice(func(d unifi.Device) int64 { return d.NumSTA }))
+
+	return w.Flush()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels renders alternating label names and values.
+func formatLabels(kv []string) string {
+	var parts []string
+	for ix := 0; ix+1 < len(kv); ix += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, kv[ix], labelEscaper.Replace(kv[ix+1])))
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -5,9 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math/rand"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/notify"
+	"github.com/johnweldon/unifi-scheduler/pkg/presence"
+	"github.com/johnweldon/unifi-scheduler/pkg/store"
 	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
 )
 
@@ -18,16 +25,132 @@ func NewAgent(s *unifi.Session, base string, opts ...ClientOpt) *Agent {
 	}
 
 	return &Agent{
-		client:    s,
-		publisher: NewPublisher(append(opts, addnl...)...),
-		base:      base,
+		client:         s,
+		publisher:      NewPublisher(append(opts, addnl...)...),
+		base:           base,
+		notifySeverity: unifi.SeverityWarning,
+		anomalySpike:   DefaultAnomalySpike,
 	}
 }
 
+// DefaultAnomalySpike is the increase in an anomaly count between polls
+// that is notified.
+var DefaultAnomalySpike int64 = 5
+
 type Agent struct {
 	client    *unifi.Session
 	publisher *Publisher
 	base      string
+
+	notifier       notify.Notifier
+	notifySeverity unifi.EventSeverity
+	notifiedAt     time.Time
+
+	anomalySpike    int64
+	clientAnomalies map[string]int64
+	deviceAnomalies map[string]int64
+
+	presence *presence.Monitor
+
+	local store.Store
+
+	heartbeatFile string
+
+	startupJitter time.Duration
+	alignTicks    bool
+	tickOffset    time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	queue     chan pending
+	queueDone chan struct{}
+	dropped   atomic.Int64
+}
+
+// pending is a queued publish or store operation.
+type pending struct {
+	desc string
+	op   func() error
+}
+
+type AgentOpt func(*Agent)
+
+// OptNotifier dispatches classified events through n.
+func OptNotifier(n notify.Notifier) AgentOpt { return func(a *Agent) { a.notifier = n } }
+
+// OptNotifySeverity sets the minimum event severity that is dispatched.
+func OptNotifySeverity(s unifi.EventSeverity) AgentOpt {
+	return func(a *Agent) { a.notifySeverity = s }
+}
+
+// OptPresenceMonitor feeds each client snapshot through m. If m has no
+// KnownStore, the known devices are persisted in the detail bucket.
+func OptPresenceMonitor(m *presence.Monitor) AgentOpt {
+	return func(a *Agent) {
+		if m != nil && m.Known == nil {
+			m.Known = &knownStore{agent: a}
+		}
+
+		a.presence = m
+	}
+}
+
+// OptStore additionally persists snapshots and events to s. The agent
+// closes s once it has stopped and its queued writes are done, which may
+// be after Stop returns if Stop timed out.
+func OptStore(s store.Store) AgentOpt { return func(a *Agent) { a.local = s } }
+
+// OptWithoutNATS disables publishing to NATS, for use with OptStore.
+func OptWithoutNATS() AgentOpt { return func(a *Agent) { a.publisher = nil } }
+
+// OptAnomalySpike notifies, as a warning, when the anomaly count of a
+// device, switch port, or client grows by at least spike between polls. A
+// spike of zero disables anomaly notifications.
+func OptAnomalySpike(spike int64) AgentOpt { return func(a *Agent) { a.anomalySpike = spike } }
+
+// OptStartupJitter delays the first poll by a random duration in [0, max),
+// spreading the load when many agents start at the same time.
+func OptStartupJitter(max time.Duration) AgentOpt {
+	return func(a *Agent) { a.startupJitter = max }
+}
+
+// OptAlignedTicks schedules each poll on a fixed wall-clock grid, at the
+// next multiple of its interval, rather than a full interval after the
+// previous poll finished, so processing time does not accumulate as drift.
+// With OptStartupJitter the grid is offset by the startup delay, keeping
+// many agents from polling at the same instant.
+func OptAlignedTicks(aligned bool) AgentOpt { return func(a *Agent) { a.alignTicks = aligned } }
+
+// OptPublishQueue decouples refreshing from publishing through a queue of
+// the given size. When the queue is full the oldest pending publish is
+// dropped. A size of zero publishes synchronously.
+func OptPublishQueue(size int) AgentOpt {
+	return func(a *Agent) {
+		a.queue = nil
+		if size > 0 {
+			a.queue = make(chan pending, size)
+		}
+	}
+}
+
+// Dropped returns the number of publishes dropped because the publish
+// queue was full.
+func (a *Agent) Dropped() int64 { return a.dropped.Load() }
+
+// ConnectionStatus describes the state of the agent's NATS connection.
+func (a *Agent) ConnectionStatus() string {
+	if a.publisher == nil {
+		return "DISABLED"
+	}
+
+	return a.publisher.Status()
+}
+
+func (a *Agent) Init(opts ...AgentOpt) {
+	for _, opt := range opts {
+		opt(a)
+	}
 }
 
 func (a *Agent) Start(ctx context.Context) error {
@@ -35,15 +158,76 @@ func (a *Agent) Start(ctx context.Context) error {
 		return errors.New("missing unifi client")
 	}
 
-	if a.publisher == nil {
-		return errors.New("missing publisher")
+	if a.publisher == nil && a.local == nil {
+		return errors.New("missing publisher or store")
 	}
 
 	if a.base == "" {
 		return errors.New("missing base name")
 	}
 
-	go a.serve(ctx)
+	a.notifiedAt = time.Now()
+
+	ctx, a.cancel = context.WithCancel(ctx)
+	a.done = make(chan struct{})
+
+	go func() {
+		defer close(a.done)
+		a.serve(ctx)
+
+		// serve is the only producer, so the queue is closed once it
+		// returns. Without a queue, serve was the only writer to the store.
+		if a.queue != nil {
+			close(a.queue)
+		} else {
+			a.closeStore()
+		}
+	}()
+
+	if a.queue != nil {
+		a.queueDone = make(chan struct{})
+
+		go func() {
+			defer close(a.queueDone)
+			a.drain()
+			a.closeStore()
+		}()
+	}
+
+	return nil
+}
+
+// Stop cancels serving, waits for any in-progress refresh to finish, then
+// drains pending publishes and closes the NATS connection. Waiting is
+// bounded by ctx; Stop may be called again to keep waiting.
+func (a *Agent) Stop(ctx context.Context) error {
+	if a.cancel == nil {
+		return errors.New("agent not started")
+	}
+
+	a.cancel()
+
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for agent to stop: %w", ctx.Err())
+	}
+
+	if a.queue != nil {
+		select {
+		case <-a.queueDone:
+		case <-ctx.Done():
+			return fmt.Errorf("draining publish queue: %w", ctx.Err())
+		}
+	}
+
+	if a.publisher == nil {
+		return nil
+	}
+
+	if err := a.publisher.Close(ctx); err != nil {
+		return fmt.Errorf("closing publisher: %w", err)
+	}
 
 	return nil
 }
@@ -51,6 +235,18 @@ func (a *Agent) Start(ctx context.Context) error {
 func (a *Agent) serve(ctx context.Context) {
 	var err error
 
+	if a.startupJitter > 0 {
+		delay := time.Duration(rand.Int63n(int64(a.startupJitter)))
+		a.tickOffset = delay
+		log.Printf("delaying startup by %s", delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+
 	eventInterval := time.After(1 * time.Second)
 	lookupInterval := time.After(7 * time.Second)
 	clientInterval := time.After(11 * time.Second)
@@ -63,31 +259,33 @@ func (a *Agent) serve(ctx context.Context) {
 			return
 
 		case <-eventInterval:
-			eventInterval = time.After(37 * time.Second)
+			eventInterval = a.after(37 * time.Second)
 			if err = a.publishEvents(); err != nil {
 				log.Printf("error: publishing events %v", err)
 			}
 
 		case <-clientInterval:
-			clientInterval = time.After(53 * time.Second)
+			clientInterval = a.after(53 * time.Second)
 			if err = a.refreshClients(); err != nil {
 				log.Printf("error: refreshing clients %v", err)
+			} else {
+				a.beat()
 			}
 
 		case <-userInterval:
-			userInterval = time.After(337 * time.Second)
+			userInterval = a.after(337 * time.Second)
 			if err = a.refreshUsers(); err != nil {
 				log.Printf("error: refreshing users %v", err)
 			}
 
 		case <-deviceInterval:
-			deviceInterval = time.After(607 * time.Second)
+			deviceInterval = a.after(607 * time.Second)
 			if err = a.refreshDevices(); err != nil {
 				log.Printf("error: refreshing devices %v", err)
 			}
 
 		case <-lookupInterval:
-			lookupInterval = time.After(997 * time.Second)
+			lookupInterval = a.after(997 * time.Second)
 			if err = a.refreshLookups(); err != nil {
 				log.Printf("error: refreshing lookups %v", err)
 			}
@@ -95,6 +293,19 @@ func (a *Agent) serve(ctx context.Context) {
 	}
 }
 
+// after returns a channel that fires after d, or at the next point on the
+// grid of d when ticks are aligned.
+func (a *Agent) after(d time.Duration) <-chan time.Time {
+	if !a.alignTicks {
+		return time.After(d)
+	}
+
+	now := time.Now()
+	next := now.Add(-a.tickOffset).Truncate(d).Add(d).Add(a.tickOffset)
+
+	return time.After(next.Sub(now))
+}
+
 func (a *Agent) publishEvents() error {
 	events, err := a.client.GetRecentEvents()
 	if err != nil {
@@ -107,6 +318,14 @@ func (a *Agent) publishEvents() error {
 		}
 	}
 
+	a.notifyEvents(events)
+
+	if err = a.persist("append events", func(ctx context.Context) error {
+		return a.local.AppendEvents(ctx, events)
+	}); err != nil {
+		return err
+	}
+
 	const maxEvents = 500
 	if len(events) > maxEvents {
 		events = events[len(events)-500:]
@@ -119,16 +338,104 @@ func (a *Agent) publishEvents() error {
 	return nil
 }
 
+// notifyEvents dispatches events newer than the last notification that
+// meet the configured severity.
+func (a *Agent) notifyEvents(events []unifi.Event) {
+	if a.notifier == nil {
+		return
+	}
+
+	latest := a.notifiedAt
+
+	for _, evt := range events {
+		if !evt.DateTime.After(a.notifiedAt) {
+			continue
+		}
+
+		if evt.DateTime.After(latest) {
+			latest = evt.DateTime
+		}
+
+		severity := evt.Severity()
+		if severity < a.notifySeverity {
+			continue
+		}
+
+		msg := notify.Message{
+			Title:    string(evt.Key),
+			Body:     evt.Message,
+			Severity: severity.String(),
+			Time:     evt.DateTime,
+			Fields:   evt.Details(),
+		}
+
+		if err := a.notifier.Notify(context.Background(), msg); err != nil {
+			log.Printf("error: notifying %q: %v", evt.Key, err)
+		}
+	}
+
+	a.notifiedAt = latest
+}
+
+// notifyAnomalies dispatches a warning for each anomaly whose count grew by
+// at least the configured spike since prev, and returns the counts to
+// compare the next poll with. Nothing is notified on the first poll.
+func (a *Agent) notifyAnomalies(prev map[string]int64, anomalies []unifi.Anomaly) map[string]int64 {
+	counts := make(map[string]int64, len(anomalies))
+	for _, anomaly := range anomalies {
+		counts[anomaly.Key()] = anomaly.Count
+	}
+
+	if a.notifier == nil || a.anomalySpike <= 0 || prev == nil || unifi.SeverityWarning < a.notifySeverity {
+		return counts
+	}
+
+	for _, anomaly := range anomalies {
+		growth := anomaly.Count - prev[anomaly.Key()]
+		if growth < a.anomalySpike {
+			continue
+		}
+
+		msg := notify.Message{
+			Title:    "anomalies",
+			Body:     fmt.Sprintf("%s %s (%s): %d anomalies, up %d since the last poll", anomaly.Kind, anomaly.Source(), anomaly.MAC, anomaly.Count, growth),
+			Severity: unifi.SeverityWarning.String(),
+			Time:     time.Now(),
+		}
+
+		if err := a.notifier.Notify(context.Background(), msg); err != nil {
+			log.Printf("error: notifying anomalies for %s: %v", anomaly.Key(), err)
+		}
+	}
+
+	return counts
+}
+
 func (a *Agent) refreshClients() error {
 	clients, err := a.client.GetClients()
 	if err != nil {
 		return fmt.Errorf("get clients: %w", err)
 	}
 
+	if a.presence != nil {
+		if err = a.presence.Observe(context.Background(), clients); err != nil {
+			log.Printf("error: observing presence %v", err)
+		}
+	}
+
+	a.clientAnomalies = a.notifyAnomalies(a.clientAnomalies, unifi.ClientAnomalies(clients))
+
 	if err = a.publish("clients", clients); err != nil {
 		return err
 	}
 
+	seen := time.Now()
+	if err = a.persist("record clients", func(ctx context.Context) error {
+		return a.local.PutClients(ctx, seen, clients)
+	}); err != nil {
+		return err
+	}
+
 	if err = a.store(DetailBucket(a.base), ActiveKey, clients); err != nil {
 		return fmt.Errorf("persisting live clients: %w", err)
 	}
@@ -162,6 +469,8 @@ func (a *Agent) refreshDevices() error {
 		return fmt.Errorf("get devices: %w", err)
 	}
 
+	a.deviceAnomalies = a.notifyAnomalies(a.deviceAnomalies, unifi.DeviceAnomalies(devices))
+
 	if err = a.publish(DevicesSubject, devices); err != nil {
 		return err
 	}
@@ -207,20 +516,79 @@ func (a *Agent) refreshLookups() error {
 	return nil
 }
 
+// enqueue runs op on the publish queue, or synchronously if there is no
+// queue. When the queue is full the oldest pending op is dropped.
+func (a *Agent) enqueue(desc string, op func() error) error {
+	if a.queue == nil {
+		return op()
+	}
+
+	p := pending{desc: desc, op: op}
+
+	for {
+		select {
+		case a.queue <- p:
+			return nil
+		default:
+		}
+
+		select {
+		case old := <-a.queue:
+			a.dropped.Add(1)
+			log.Printf("publish queue full: dropped %s", old.desc)
+		default:
+		}
+	}
+}
+
+// closeStore closes the local store, if there is one.
+func (a *Agent) closeStore() {
+	if a.local == nil {
+		return
+	}
+
+	if err := a.local.Close(); err != nil {
+		log.Printf("error: closing store: %v", err)
+	}
+}
+
+// drain runs queued ops until the queue is closed.
+func (a *Agent) drain() {
+	for p := range a.queue {
+		if err := p.op(); err != nil {
+			log.Printf("error: %s: %v", p.desc, err)
+		}
+	}
+}
+
 func (a *Agent) publish(subject string, msg any) error {
-	if err := a.publisher.Publish(subSubject(a.base, subject), msg); err != nil {
-		return fmt.Errorf("publish: %w", err)
+	if a.publisher == nil {
+		return nil
 	}
 
-	return nil
+	subject = subSubject(a.base, subject)
+
+	return a.enqueue("publish "+subject, func() error {
+		if err := a.publisher.Publish(subject, msg); err != nil {
+			return fmt.Errorf("publish: %w", err)
+		}
+
+		return nil
+	})
 }
 
 func (a *Agent) publishStream(stream, subject string, msg any) error {
-	if err := a.publisher.PublishStream(stream, subject, msg); err != nil {
-		return fmt.Errorf("publish stream %q: %w", stream, err)
+	if a.publisher == nil {
+		return nil
 	}
 
-	return nil
+	return a.enqueue("publish stream "+stream, func() error {
+		if err := a.publisher.PublishStream(stream, subject, msg); err != nil {
+			return fmt.Errorf("publish stream %q: %w", stream, err)
+		}
+
+		return nil
+	})
 }
 
 func (a *Agent) store(bucket, key string, val any) error {
@@ -229,17 +597,83 @@ func (a *Agent) store(bucket, key string, val any) error {
 		return nil
 	}
 
-	if err := a.publisher.Store(bucket, norm, val); err != nil {
-		return fmt.Errorf("storing key %q: %w", norm, err)
+	// Only the details are kept locally; the lookups are derived from them.
+	if bucket == DetailBucket(a.base) {
+		if err := a.persist("snapshot "+norm, func(ctx context.Context) error {
+			return a.local.PutSnapshot(ctx, norm, val)
+		}); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	if a.publisher == nil {
+		return nil
+	}
+
+	return a.enqueue("store "+bucket+"/"+norm, func() error {
+		if err := a.publisher.Store(bucket, norm, val); err != nil {
+			return fmt.Errorf("storing key %q: %w", norm, err)
+		}
+
+		return nil
+	})
+}
+
+// persist runs op against the local store, if there is one.
+func (a *Agent) persist(desc string, op func(context.Context) error) error {
+	if a.local == nil {
+		return nil
+	}
+
+	return a.enqueue(desc, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultWriteTimeout)
+		defer cancel()
+
+		if err := op(ctx); err != nil {
+			return fmt.Errorf("%s: %w", desc, err)
+		}
+
+		return nil
+	})
+}
+
+// knownStore persists the presence monitor's known devices.
+type knownStore struct {
+	agent *Agent
+}
+
+func (k *knownStore) Load() ([]unifi.MAC, error) {
+	var macs []unifi.MAC
+
+	if k.agent.publisher == nil {
+		err := k.agent.local.GetSnapshot(context.Background(), KnownKey, &macs)
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, nil
+		}
+
+		return macs, err
+	}
+
+	if err := k.agent.publisher.Get(DetailBucket(k.agent.base), KnownKey, &macs); err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return macs, nil
+}
+
+func (k *knownStore) Save(macs []unifi.MAC) error {
+	return k.agent.store(DetailBucket(k.agent.base), KnownKey, macs)
 }
 
 const (
 	ActiveKey  = "active"
 	DevicesKey = "devices"
 	EventsKey  = "events"
+	KnownKey   = "known"
 
 	DevicesSubject = "devices"
 )
@@ -0,0 +1,97 @@
+package nats
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/store"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+// memStore is a Store that keeps nothing.
+type memStore struct{}
+
+func (memStore) PutSnapshot(context.Context, string, any) error              { return nil }
+func (memStore) GetSnapshot(context.Context, string, any) error              { return store.ErrNotFound }
+func (memStore) PutClients(context.Context, time.Time, []unifi.Client) error { return nil }
+func (memStore) AppendEvents(context.Context, []unifi.Event) error           { return nil }
+func (memStore) Close() error                                                { return nil }
+
+func TestAgentStopTwice(t *testing.T) {
+	for _, queue := range []int{0, 16} {
+		a := NewAgent(&unifi.Session{}, "test")
+		a.Init(OptStore(memStore{}), OptWithoutNATS(), OptPublishQueue(queue))
+
+		if err := a.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		for i := 0; i < 2; i++ {
+			if err := a.Stop(ctx); err != nil {
+				t.Errorf("queue %d: stop %d: %v", queue, i+1, err)
+			}
+		}
+
+		cancel()
+	}
+}
+
+// closeStore records when it is closed.
+type closeStore struct {
+	memStore
+
+	closed atomic.Bool
+}
+
+func (s *closeStore) Close() error {
+	s.closed.Store(true)
+
+	return nil
+}
+
+func TestAgentClosesStoreAfterDrain(t *testing.T) {
+	st := &closeStore{}
+
+	a := NewAgent(&unifi.Session{}, "test")
+	a.Init(OptStore(st), OptWithoutNATS(), OptPublishQueue(4))
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+
+	_ = a.enqueue("slow write", func() error {
+		<-release
+
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := a.Stop(ctx); err == nil {
+		t.Fatal("Stop returned before the queued write finished")
+	}
+
+	if st.closed.Load() {
+		t.Fatal("store closed while a write was in progress")
+	}
+
+	close(release)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := a.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if !st.closed.Load() {
+		t.Error("store not closed after Stop")
+	}
+}
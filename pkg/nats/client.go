@@ -2,9 +2,12 @@ package nats
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -14,6 +17,8 @@ import (
 var (
 	DefaultConnectTimeout = 15 * time.Second
 	DefaultWriteTimeout   = 30 * time.Second
+	DefaultReconnectWait  = 2 * time.Second
+	DefaultOpTimeout      = 10 * time.Second
 )
 
 type ClientOpt func(*Client)
@@ -30,12 +35,61 @@ func OptBuckets(names ...string) ClientOpt {
 	return func(c *Client) { c.buckets = append(c.buckets, names...) }
 }
 
+// OptConnectTimeout sets the timeout for establishing the NATS connection.
+func OptConnectTimeout(d time.Duration) ClientOpt { return func(c *Client) { c.connectTimeout = d } }
+
+// OptOpTimeout sets the timeout for individual JetStream and KV operations.
+func OptOpTimeout(d time.Duration) ClientOpt { return func(c *Client) { c.opTimeout = d } }
+
+// OptStreamReplicas sets the replica count of created streams. It must come
+// after any OptStreamConfig.
+func OptStreamReplicas(r int) ClientOpt { return func(c *Client) { c.streamConfig.Replicas = r } }
+
+// OptBucketReplicas sets the replica count of created KV buckets. It must
+// come after any OptBucketConfig.
+func OptBucketReplicas(r int) ClientOpt { return func(c *Client) { c.bucketConfig.Replicas = r } }
+
+// OptStreamConfig sets the limits used when creating or updating streams.
+func OptStreamConfig(cfg StreamConfig) ClientOpt { return func(c *Client) { c.streamConfig = cfg } }
+
+// OptBucketConfig sets the limits used when creating or updating KV buckets.
+func OptBucketConfig(cfg BucketConfig) ClientOpt { return func(c *Client) { c.bucketConfig = cfg } }
+
+// StreamConfig describes the retention limits of the JetStream streams.
+// Zero values mean unlimited, except Replicas which defaults to 1.
+type StreamConfig struct {
+	MaxAge   time.Duration
+	MaxBytes int64
+	MaxMsgs  int64
+	Replicas int
+}
+
+// BucketConfig describes the retention limits of the KV buckets.
+// Zero values mean unlimited, except Replicas which defaults to 1.
+type BucketConfig struct {
+	TTL      time.Duration
+	MaxBytes int64
+	Replicas int
+}
+
+var (
+	DefaultStreamConfig = StreamConfig{MaxMsgs: 1000, Replicas: 3}
+	DefaultBucketConfig = BucketConfig{TTL: 90 * 24 * time.Hour, Replicas: 3}
+)
+
 type Client struct {
-	connURL   string
-	credsFile string
-	conn      *nats.Conn
-	streams   []string
-	buckets   []string
+	mu sync.Mutex
+
+	connURL        string
+	credsFile      string
+	conn           *nats.Conn
+	ready          atomic.Bool
+	streams        []string
+	buckets        []string
+	streamConfig   StreamConfig
+	bucketConfig   BucketConfig
+	connectTimeout time.Duration
+	opTimeout      time.Duration
 }
 
 func (n *Client) Init(opts ...ClientOpt) {
@@ -44,31 +98,116 @@ func (n *Client) Init(opts ...ClientOpt) {
 	}
 }
 
+// Flush waits until all pending messages have been sent to the server.
+func (n *Client) Flush(ctx context.Context) error {
+	if !n.IsConnected() {
+		return nil
+	}
+
+	return n.conn.FlushWithContext(ctx)
+}
+
+// Close drains pending messages and closes the NATS connection, waiting
+// until the connection is closed or ctx is done. The Client reconnects if
+// it is used again.
+func (n *Client) Close(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.conn == nil {
+		return nil
+	}
+
+	conn := n.conn
+	n.conn = nil
+	n.ready.Store(false)
+
+	if conn.IsClosed() {
+		return nil
+	}
+
+	if err := conn.Drain(); err != nil {
+		conn.Close()
+
+		return fmt.Errorf("close: draining connection: %w", err)
+	}
+
+	tick := time.NewTicker(10 * time.Millisecond)
+	defer tick.Stop()
+
+	for !conn.IsClosed() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+
+			return fmt.Errorf("close: waiting for drain: %w", ctx.Err())
+		case <-tick.C:
+		}
+	}
+
+	return nil
+}
+
+// Status describes the state of the NATS connection.
+func (n *Client) Status() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.conn == nil {
+		return "NOT_CONNECTED"
+	}
+
+	return n.conn.Status().String()
+}
+
+// IsConnected reports whether the NATS connection is currently up.
+func (n *Client) IsConnected() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.conn != nil && n.conn.IsConnected()
+}
+
+// ensureConnection connects to NATS if needed. The connection reconnects
+// on its own after a server restart; streams and buckets are re-ensured
+// once it does.
 func (n *Client) ensureConnection() error {
 	var err error
 
-	if n.conn != nil {
-		if n.conn.IsConnected() {
-			return nil
-		}
+	n.mu.Lock()
+	defer n.mu.Unlock()
 
-		log.Print("ensureConnection: was not connected, retrying")
+	if n.conn != nil && n.conn.IsClosed() {
+		log.Print("ensureConnection: connection closed, reconnecting")
 
-		n.conn.Close()
 		n.conn = nil
 	}
 
-	opts := []nats.Option{
-		nats.Timeout(DefaultConnectTimeout),
-		nats.FlusherTimeout(DefaultWriteTimeout),
+	if n.conn == nil {
+		n.ready.Store(false)
+
+		opts := []nats.Option{
+			nats.Timeout(orDefault(n.connectTimeout, DefaultConnectTimeout)),
+			nats.FlusherTimeout(DefaultWriteTimeout),
+		}
+
+		opts = append(opts, ReconnectOptions(func() { n.ready.Store(false) })...)
+
+		if len(n.credsFile) != 0 {
+			opts = append(opts, nats.UserCredentials(n.credsFile))
+		}
+
+		if n.conn, err = nats.Connect(n.connURL, opts...); err != nil {
+			return fmt.Errorf("ensureConnection: connecting to NATS: %w", err)
+		}
 	}
 
-	if len(n.credsFile) != 0 {
-		opts = append(opts, nats.UserCredentials(n.credsFile))
+	if !n.conn.IsConnected() {
+		return fmt.Errorf("ensureConnection: not connected to NATS (%s)", n.conn.Status())
 	}
 
-	if n.conn, err = nats.Connect(n.connURL, opts...); err != nil {
-		return fmt.Errorf("ensureConnection: connecting to NATS: %w", err)
+	if n.ready.Load() {
+		return nil
 	}
 
 	if err = n.ensureStreams(); err != nil {
@@ -79,6 +218,65 @@ func (n *Client) ensureConnection() error {
 		return fmt.Errorf("ensureConnection: ensuring buckets:  %w", err)
 	}
 
+	n.ready.Store(true)
+
+	return nil
+}
+
+// ReconnectOptions returns the options used to keep a NATS connection alive
+// across server restarts, including when the server is not up yet. Any
+// onReconnect funcs are called after each successful reconnect.
+func ReconnectOptions(onReconnect ...func()) []nats.Option {
+	return []nats.Option{
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(DefaultReconnectWait),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Printf("nats: disconnected: %v", err)
+			}
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			log.Printf("nats: reconnected to %s", c.ConnectedUrlRedacted())
+
+			for _, fn := range onReconnect {
+				fn()
+			}
+		}),
+	}
+}
+
+func (n *Client) retrieve(bucket, key string, into any) error {
+	var (
+		err   error
+		js    jetstream.JetStream
+		kv    jetstream.KeyValue
+		entry jetstream.KeyValueEntry
+	)
+
+	if err = n.ensureConnection(); err != nil {
+		return fmt.Errorf("retrieve: not connected: %w", err)
+	}
+
+	if js, err = jetstream.New(n.conn); err != nil {
+		return fmt.Errorf("retrieve: cannot get jetstream: %w", err)
+	}
+
+	ctx, cancel := n.opContext()
+	defer cancel()
+
+	if kv, err = js.KeyValue(ctx, bucket); err != nil {
+		return fmt.Errorf("retrieve: cannot get bucket %q: %w", bucket, err)
+	}
+
+	if entry, err = kv.Get(ctx, key); err != nil {
+		return fmt.Errorf("retrieve: cannot get %q in bucket %q: %w", key, bucket, err)
+	}
+
+	if err = json.Unmarshal(entry.Value(), into); err != nil {
+		return fmt.Errorf("retrieve: cannot unmarshal %q from bucket %q: %w", key, bucket, err)
+	}
+
 	return nil
 }
 
@@ -92,6 +290,9 @@ func (n *Client) ensureStreams() error {
 		return fmt.Errorf("ensureStreams: cannot get jetstream: %w", err)
 	}
 
+	ctx, cancel := n.opContext()
+	defer cancel()
+
 	for _, stream := range n.streams {
 		cfg := jetstream.StreamConfig{
 			Name:       stream,
@@ -99,21 +300,27 @@ func (n *Client) ensureStreams() error {
 			Duplicates: 1 * time.Hour,
 			Discard:    jetstream.DiscardOld,
 			Retention:  jetstream.LimitsPolicy,
-			MaxMsgs:    1000,
-			Replicas:   3,
+			MaxAge:     n.streamConfig.MaxAge,
+			MaxBytes:   limit(n.streamConfig.MaxBytes),
+			MaxMsgs:    limit(n.streamConfig.MaxMsgs),
+			Replicas:   n.streamConfig.Replicas,
+		}
+
+		if cfg.MaxAge > 0 && cfg.MaxAge < cfg.Duplicates {
+			cfg.Duplicates = cfg.MaxAge
 		}
 
-		if _, err = js.Stream(context.Background(), stream); err != nil {
+		if _, err = js.Stream(ctx, stream); err != nil {
 			if !errors.Is(err, jetstream.ErrStreamNotFound) {
 				return fmt.Errorf("ensureStreams: getting stream info %q: %w", stream, err)
 			}
 
-			if _, err = js.CreateStream(context.Background(), cfg); err != nil {
+			if _, err = js.CreateStream(ctx, cfg); err != nil {
 				return fmt.Errorf("ensureStreams: creating stream %q: %w", stream, err)
 			}
 		}
 
-		if _, err = js.UpdateStream(context.Background(), cfg); err != nil {
+		if _, err = js.UpdateStream(ctx, cfg); err != nil {
 			return fmt.Errorf("ensureStreams: updating stream %q: %w", stream, err)
 		}
 	}
@@ -131,23 +338,55 @@ func (n *Client) ensureBuckets() error {
 		return fmt.Errorf("ensureBuckets: cannot get jetstream: %w", err)
 	}
 
+	ctx, cancel := n.opContext()
+	defer cancel()
+
 	for _, bucket := range n.buckets {
-		if _, err = js.KeyValue(context.Background(), bucket); err != nil {
+		cfg := jetstream.KeyValueConfig{
+			Bucket:   bucket,
+			TTL:      n.bucketConfig.TTL,
+			MaxBytes: limit(n.bucketConfig.MaxBytes),
+			Replicas: n.bucketConfig.Replicas,
+		}
+
+		if _, err = js.KeyValue(ctx, bucket); err != nil {
 			if !errors.Is(err, jetstream.ErrBucketNotFound) {
 				return fmt.Errorf("ensureBuckets: getting bucket %q: %w", bucket, err)
 			}
 
-			cfg := jetstream.KeyValueConfig{
-				Bucket:   bucket,
-				TTL:      90 * 24 * time.Hour,
-				Replicas: 3,
-			}
-
-			if _, err = js.CreateKeyValue(context.Background(), cfg); err != nil {
+			if _, err = js.CreateKeyValue(ctx, cfg); err != nil {
 				return fmt.Errorf("ensureBuckets: creating bucket %q: %w", bucket, err)
 			}
+
+			continue
+		}
+
+		if _, err = js.UpdateKeyValue(ctx, cfg); err != nil {
+			return fmt.Errorf("ensureBuckets: updating bucket %q: %w", bucket, err)
 		}
 	}
 
 	return nil
 }
+
+// opContext returns a context bounded by the operation timeout.
+func (n *Client) opContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), orDefault(n.opTimeout, DefaultOpTimeout))
+}
+
+func orDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+
+	return d
+}
+
+// limit maps the zero value to JetStream's "unlimited".
+func limit(v int64) int64 {
+	if v <= 0 {
+		return -1
+	}
+
+	return v
+}
@@ -0,0 +1,62 @@
+package nats
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OptHeartbeatFile writes the time of each successful client refresh to
+// path, for a liveness probe that doesn't query the controller itself.
+func OptHeartbeatFile(path string) AgentOpt { return func(a *Agent) { a.heartbeatFile = path } }
+
+// beat records a successful refresh in the heartbeat file, if any.
+func (a *Agent) beat() {
+	if a.heartbeatFile == "" {
+		return
+	}
+
+	if err := WriteHeartbeat(a.heartbeatFile, time.Now()); err != nil {
+		log.Printf("error: writing heartbeat %v", err)
+	}
+}
+
+// WriteHeartbeat atomically replaces the file at path with t.
+func WriteHeartbeat(path string, t time.Time) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err = fmt.Fprintln(tmp, t.UTC().Format(time.RFC3339)); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// ReadHeartbeat returns the time recorded in the heartbeat file at path.
+func ReadHeartbeat(path string) (time.Time, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(b)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading heartbeat %s: %w", path, err)
+	}
+
+	return t, nil
+}
@@ -1,7 +1,6 @@
 package nats
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 
@@ -11,6 +10,8 @@ import (
 
 func NewPublisher(opts ...ClientOpt) *Publisher {
 	p := &Publisher{}
+	p.streamConfig = DefaultStreamConfig
+	p.bucketConfig = DefaultBucketConfig
 	p.Init(opts...)
 	return p
 }
@@ -21,6 +22,8 @@ type Publisher struct {
 
 func (n *Publisher) Store(bucket, key string, val any) error { return n.store(bucket, key, val) }
 
+func (n *Publisher) Get(bucket, key string, into any) error { return n.retrieve(bucket, key, into) }
+
 func (n *Publisher) Publish(subject string, msg any) error { return n.publish(subject, msg) }
 
 func (n *Publisher) PublishStream(stream, subject string, msg any) error {
@@ -63,6 +66,9 @@ func (n *Publisher) publishStream(stream string, msg any) error {
 		return fmt.Errorf("publishStream: cannot get jetstream: %w", err)
 	}
 
+	ctx, cancel := n.opContext()
+	defer cancel()
+
 	if data, err = json.Marshal(msg); err != nil {
 		return fmt.Errorf("publishStream: cannot marshal data: %w", err)
 	}
@@ -78,7 +84,7 @@ func (n *Publisher) publishStream(stream string, msg any) error {
 		Data:    data,
 	}
 
-	if _, err = js.PublishMsg(context.Background(), pmsg); err != nil {
+	if _, err = js.PublishMsg(ctx, pmsg); err != nil {
 		return fmt.Errorf("publishStream: cannot publish data: %w", err)
 	}
 
@@ -101,7 +107,10 @@ func (n *Publisher) store(bucket, key string, val any) error {
 		return fmt.Errorf("store: cannot get jetstream: %w", err)
 	}
 
-	if kv, err = js.KeyValue(context.Background(), bucket); err != nil {
+	ctx, cancel := n.opContext()
+	defer cancel()
+
+	if kv, err = js.KeyValue(ctx, bucket); err != nil {
 		return fmt.Errorf("store: cannot get bucket %q: %w", bucket, err)
 	}
 
@@ -109,7 +118,7 @@ func (n *Publisher) store(bucket, key string, val any) error {
 		return fmt.Errorf("store: cannot marshal data: %w", err)
 	}
 
-	if _, err = kv.Put(context.Background(), key, data); err != nil {
+	if _, err = kv.Put(ctx, key, data); err != nil {
 		return fmt.Errorf("store: cannot put in bucket %q: %w", bucket, err)
 	}
 
@@ -2,7 +2,6 @@ package nats
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -32,37 +31,6 @@ func (s *Subscriber) SubscribeStream(ctx context.Context, subjects ...string) (<
 	return s.subscribeStream(ctx, subjects...)
 }
 
-func (s *Subscriber) retrieve(bucket, key string, into any) error {
-	var (
-		err   error
-		js    jetstream.JetStream
-		kv    jetstream.KeyValue
-		entry jetstream.KeyValueEntry
-	)
-
-	if err = s.ensureConnection(); err != nil {
-		return fmt.Errorf("retrieve: not connected: %w", err)
-	}
-
-	if js, err = jetstream.New(s.conn); err != nil {
-		return fmt.Errorf("retrieve: cannot get jetstream: %w", err)
-	}
-
-	if kv, err = js.KeyValue(context.Background(), bucket); err != nil {
-		return fmt.Errorf("retrieve: cannot get bucket %q: %w", bucket, err)
-	}
-
-	if entry, err = kv.Get(context.Background(), key); err != nil {
-		return fmt.Errorf("retrieve: cannot get %q in bucket %q: %w", key, bucket, err)
-	}
-
-	if err = json.Unmarshal(entry.Value(), into); err != nil {
-		return fmt.Errorf("retrieve: cannot unmarshal %q from bucket %q: %w", key, bucket, err)
-	}
-
-	return nil
-}
-
 func (s *Subscriber) subscribe(ctx context.Context, subjects ...string) (<-chan string, error) {
 	var err error
 
@@ -155,6 +123,9 @@ func (s *Subscriber) subscribeStream(ctx context.Context, subjects ...string) (<
 		return nil, fmt.Errorf("subscribe: cannot get jetstream: %w", err)
 	}
 
+	ctx, cancel := s.opContext()
+	defer cancel()
+
 	var consumers []jetstream.Consumer
 
 	cfg := jetstream.ConsumerConfig{
@@ -163,7 +134,7 @@ func (s *Subscriber) subscribeStream(ctx context.Context, subjects ...string) (<
 
 	for _, stream := range streams {
 		var cons jetstream.Consumer
-		if cons, err = js.CreateOrUpdateConsumer(context.Background(), stream, cfg); err != nil {
+		if cons, err = js.CreateOrUpdateConsumer(ctx, stream, cfg); err != nil {
 			return nil, fmt.Errorf("subscribe: cannot create consumer: %w", err)
 		}
 
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Email sends the message via SMTP.
+type Email struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (e *Email) Notify(ctx context.Context, msg Message) error {
+	if len(e.Addr) == 0 {
+		return fmt.Errorf("notify: missing smtp address")
+	}
+
+	if len(e.From) == 0 {
+		return fmt.Errorf("notify: missing sender")
+	}
+
+	if len(e.To) == 0 {
+		return fmt.Errorf("notify: missing recipients")
+	}
+
+	host, _, err := net.SplitHostPort(e.Addr)
+	if err != nil {
+		return fmt.Errorf("notify: parsing smtp address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if len(e.Username) > 0 {
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+
+	from, to, body, err := e.message(msg)
+	if err != nil {
+		return err
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- smtp.SendMail(e.Addr, auth, from, to, body) }()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err = <-errc:
+		if err != nil {
+			return fmt.Errorf("notify: sending mail: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// message builds the mail for msg, and returns it with the bare sender and
+// recipient addresses for the envelope. The addresses are parsed, and the
+// subject encoded, so that neither can add headers of their own.
+func (e *Email) message(msg Message) (from string, to []string, body []byte, err error) {
+	sender, err := mail.ParseAddress(e.From)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("notify: parsing sender %q: %w", e.From, err)
+	}
+
+	var recipients []string
+
+	for _, addr := range e.To {
+		rcpt, err := mail.ParseAddress(addr)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("notify: parsing recipient %q: %w", addr, err)
+		}
+
+		to = append(to, rcpt.Address)
+		recipients = append(recipients, rcpt.String())
+	}
+
+	when := msg.Time
+	if when.IsZero() {
+		when = time.Now()
+	}
+
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.Title)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sender)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", when.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&buf, "%s\r\n", msg.String())
+
+	return sender.Address, to, buf.Bytes(), nil
+}
+
+var _ Notifier = (*Email)(nil)
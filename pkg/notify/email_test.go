@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailMessageHeaders(t *testing.T) {
+	e := &Email{From: "Agent <agent@example.com>", To: []string{"ops@example.com", "Zoë <zoe@example.com>"}}
+
+	from, to, body, err := e.message(Message{
+		Title: "Küche\r\nBcc: victim@example.com",
+		Body:  "joined",
+		Time:  time.Unix(1_700_000_000, 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if from != "agent@example.com" {
+		t.Errorf("got sender %q", from)
+	}
+
+	if strings.Join(to, ",") != "ops@example.com,zoe@example.com" {
+		t.Errorf("got recipients %q", to)
+	}
+
+	header, _, _ := strings.Cut(string(body), "\r\n\r\n")
+
+	for _, line := range strings.Split(header, "\r\n") {
+		if strings.HasPrefix(line, "Bcc:") {
+			t.Errorf("title added a header: %q", line)
+		}
+
+		for _, r := range line {
+			if r > 127 {
+				t.Errorf("header is not ASCII: %q", line)
+
+				break
+			}
+		}
+	}
+
+	if !strings.Contains(header, "Subject: =?utf-8?q?") {
+		t.Errorf("subject not encoded in:\n%s", header)
+	}
+}
+
+func TestEmailMessageBadAddress(t *testing.T) {
+	e := &Email{From: "agent@example.com", To: []string{"ops@example.com\r\nBcc: victim@example.com"}}
+
+	if _, _, _, err := e.message(Message{Title: "test"}); err == nil {
+		t.Error("got no error for a recipient with a header in it")
+	}
+}
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Message describes a single notification. Fields holds structured details,
+// such as the MAC of the client concerned, for destinations that keep them.
+type Message struct {
+	Title    string            `json:"title,omitempty"`
+	Body     string            `json:"body,omitempty"`
+	Severity string            `json:"severity,omitempty"`
+	Time     time.Time         `json:"time,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+func (m Message) String() string {
+	if len(m.Severity) == 0 {
+		return fmt.Sprintf("%s: %s", m.Title, m.Body)
+	}
+
+	return fmt.Sprintf("[%s] %s: %s", m.Severity, m.Title, m.Body)
+}
+
+// Notifier delivers messages to some destination.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Multi fans a message out to all the contained notifiers.
+type Multi []Notifier
+
+// Notify delivers msg to every notifier, collecting any errors.
+func (m Multi) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+
+	for _, n := range m {
+		if n == nil {
+			continue
+		}
+
+		if err := n.Notify(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+var _ Notifier = Multi(nil)
@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var DefaultTimeout = 15 * time.Second
+
+// Webhook POSTs the message as JSON to an arbitrary URL.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w *Webhook) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, w.Client, w.URL, msg)
+}
+
+// Slack posts the message to a Slack incoming webhook.
+type Slack struct {
+	WebhookURL string
+	Channel    string
+	Username   string
+	Client     *http.Client
+}
+
+func (s *Slack) Notify(ctx context.Context, msg Message) error {
+	payload := struct {
+		Text     string `json:"text"`
+		Channel  string `json:"channel,omitempty"`
+		Username string `json:"username,omitempty"`
+	}{
+		Text:     msg.String(),
+		Channel:  s.Channel,
+		Username: s.Username,
+	}
+
+	return postJSON(ctx, s.Client, s.WebhookURL, payload)
+}
+
+func postJSON(ctx context.Context, client *http.Client, u string, payload any) error {
+	if len(u) == 0 {
+		return fmt.Errorf("notify: missing url")
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout} // nolint:exhaustivestruct
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("notify: cannot marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("notify: building request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: posting: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || http.StatusBadRequest <= resp.StatusCode {
+		return fmt.Errorf("notify: http error: %s", resp.Status)
+	}
+
+	return nil
+}
+
+var (
+	_ Notifier = (*Webhook)(nil)
+	_ Notifier = (*Slack)(nil)
+)
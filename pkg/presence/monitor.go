@@ -0,0 +1,251 @@
+package presence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/notify"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+// Rule describes clients to watch for, and when they are of interest.
+type Rule struct {
+	Name string `mapstructure:"name"`
+
+	// Match holds glob patterns compared against the client MAC and
+	// names.
+	Match []string `mapstructure:"match"`
+
+	// From and To bound the time of day ("15:04") the rule applies. The
+	// window may wrap midnight. If both are empty the rule always applies.
+	From string `mapstructure:"from"`
+	To   string `mapstructure:"to"`
+}
+
+// timeOfDay is the layout of Rule.From and Rule.To.
+const timeOfDay = "15:04"
+
+// Validate reports a From or To that is not a time of day, or one set
+// without the other.
+func (r Rule) Validate() error {
+	if len(r.From) == 0 && len(r.To) == 0 {
+		return nil
+	}
+
+	if len(r.From) == 0 || len(r.To) == 0 {
+		return errors.New("from and to must be set together")
+	}
+
+	for _, v := range []string{r.From, r.To} {
+		if _, err := time.Parse(timeOfDay, v); err != nil {
+			return fmt.Errorf("time of day %q is not HH:MM", v)
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether the client matches any of the rule patterns.
+func (r Rule) Matches(client *unifi.Client) bool { return matchAny(r.Match, client) }
+
+// Active reports whether t is within the rule's time window. A rule that
+// does not Validate is never active.
+func (r Rule) Active(t time.Time) bool {
+	if len(r.From) == 0 || len(r.To) == 0 {
+		return true
+	}
+
+	from, err := time.Parse(timeOfDay, r.From)
+	if err != nil {
+		return false
+	}
+
+	to, err := time.Parse(timeOfDay, r.To)
+	if err != nil {
+		return false
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	start := from.Hour()*60 + from.Minute()
+	end := to.Hour()*60 + to.Minute()
+
+	if start <= end {
+		return start <= now && now < end
+	}
+
+	return start <= now || now < end
+}
+
+// KnownStore persists the set of previously seen MACs.
+type KnownStore interface {
+	Load() ([]unifi.MAC, error)
+	Save([]unifi.MAC) error
+}
+
+// Monitor compares successive client snapshots and notifies when a client
+// matching a Rule connects, or when a never before seen client connects.
+type Monitor struct {
+	Rules    []Rule
+	Notifier notify.Notifier
+
+	// AlertUnknown enables notification of clients not in the known set.
+	AlertUnknown bool
+
+	// Allow holds glob patterns of clients that are always considered known.
+	Allow []string
+
+	// Known persists the known set across restarts; optional.
+	Known KnownStore
+
+	previous map[unifi.MAC]unifi.Client
+	known    map[unifi.MAC]bool
+}
+
+// Observe processes a new snapshot of connected clients.
+func (m *Monitor) Observe(ctx context.Context, clients []unifi.Client) error {
+	var errs []error
+
+	if err := m.loadKnown(clients); err != nil {
+		errs = append(errs, err)
+	}
+
+	current := map[unifi.MAC]unifi.Client{}
+	for _, client := range clients {
+		current[client.MAC] = client
+	}
+
+	first := m.previous == nil
+	joined, _ := Diff(m.previous, current)
+	m.previous = current
+
+	now := time.Now()
+	changed := false
+
+	for ix := range joined {
+		client := &joined[ix]
+
+		if !first {
+			for _, rule := range m.Rules {
+				if rule.Active(now) && rule.Matches(client) {
+					errs = append(errs, m.notify(ctx, rule.Name, "connected", client))
+				}
+			}
+		}
+
+		// Without the known set (it failed to load) no client is new.
+		if m.known == nil || m.known[client.MAC] {
+			continue
+		}
+
+		m.known[client.MAC] = true
+		changed = true
+
+		if m.AlertUnknown && !matchAny(m.Allow, client) {
+			errs = append(errs, m.notify(ctx, "unknown device", "joined the network", client))
+		}
+	}
+
+	if changed && m.Known != nil {
+		if err := m.Known.Save(m.knownMACs()); err != nil {
+			errs = append(errs, fmt.Errorf("saving known devices: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Diff returns the clients that are in cur but not prev, and those in prev
+// but not in cur.
+func Diff(prev, cur map[unifi.MAC]unifi.Client) (joined, left []unifi.Client) {
+	for mac, client := range cur {
+		if _, ok := prev[mac]; !ok {
+			joined = append(joined, client)
+		}
+	}
+
+	for mac, client := range prev {
+		if _, ok := cur[mac]; !ok {
+			left = append(left, client)
+		}
+	}
+
+	unifi.ClientDefault.Sort(joined)
+	unifi.ClientDefault.Sort(left)
+
+	return joined, left
+}
+
+func (m *Monitor) notify(ctx context.Context, title, what string, client *unifi.Client) error {
+	if m.Notifier == nil {
+		return nil
+	}
+
+	msg := notify.Message{
+		Title:    title,
+		Body:     fmt.Sprintf("%s (%s, %s) %s", client.DisplayName(), client.MAC, client.DisplayIP(unifi.DisplayOptions{}), what),
+		Severity: unifi.SeverityWarning.String(),
+		Time:     time.Now(),
+	}
+
+	if err := m.Notifier.Notify(ctx, msg); err != nil {
+		return fmt.Errorf("notifying %q: %w", title, err)
+	}
+
+	return nil
+}
+
+// loadKnown populates the known set on first use. If nothing has been
+// persisted yet, the current snapshot is taken as the baseline. If loading
+// fails the known set is left unset, so the next snapshot tries again,
+// rather than replacing what was persisted.
+func (m *Monitor) loadKnown(clients []unifi.Client) error {
+	if m.known != nil {
+		return nil
+	}
+
+	var macs []unifi.MAC
+
+	if m.Known != nil {
+		var err error
+		if macs, err = m.Known.Load(); err != nil {
+			return fmt.Errorf("loading known devices: %w", err)
+		}
+	}
+
+	m.known = map[unifi.MAC]bool{}
+
+	for _, mac := range macs {
+		m.known[mac] = true
+	}
+
+	if len(m.known) > 0 {
+		return nil
+	}
+
+	for _, client := range clients {
+		m.known[client.MAC] = true
+	}
+
+	if m.Known != nil {
+		if err := m.Known.Save(m.knownMACs()); err != nil {
+			return fmt.Errorf("saving known devices: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Monitor) knownMACs() []unifi.MAC {
+	macs := make([]unifi.MAC, 0, len(m.known))
+	for mac := range m.known {
+		macs = append(macs, mac)
+	}
+
+	return macs
+}
+
+func matchAny(patterns []string, client *unifi.Client) bool {
+	return unifi.MatchAny(patterns, client.MAC.String(), client.Name, client.Hostname, client.DisplayName())
+}
@@ -0,0 +1,143 @@
+package presence
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/notify"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+func TestRuleValidate(t *testing.T) {
+	tests := []struct {
+		from, to string
+		valid    bool
+	}{
+		{"", "", true},
+		{"22:00", "06:30", true},
+		{"9:00", "17:00", true},
+		{"22:00", "", false},
+		{"", "06:30", false},
+		{"10pm", "06:30", false},
+		{"22:00", "25:00", false},
+	}
+
+	for _, tc := range tests {
+		r := Rule{Name: "test", From: tc.from, To: tc.to}
+
+		if err := r.Validate(); (err == nil) != tc.valid {
+			t.Errorf("from %q to %q: got %v, want valid %t", tc.from, tc.to, err, tc.valid)
+		}
+	}
+}
+
+func TestRuleActive(t *testing.T) {
+	night := Rule{From: "22:00", To: "06:30"}
+
+	tests := []struct {
+		at     string
+		active bool
+	}{
+		{"21:59", false},
+		{"22:00", true},
+		{"03:00", true},
+		{"06:29", true},
+		{"06:30", false},
+		{"12:00", false},
+	}
+
+	for _, tc := range tests {
+		at, _ := time.Parse(timeOfDay, tc.at)
+
+		if got := night.Active(at); got != tc.active {
+			t.Errorf("at %s: got active %t, want %t", tc.at, got, tc.active)
+		}
+	}
+}
+
+// flakyKnown is a KnownStore whose first Load fails.
+type flakyKnown struct {
+	stored []unifi.MAC
+	loads  int
+	saves  [][]unifi.MAC
+}
+
+func (k *flakyKnown) Load() ([]unifi.MAC, error) {
+	k.loads++
+	if k.loads == 1 {
+		return nil, errors.New("not connected")
+	}
+
+	return k.stored, nil
+}
+
+func (k *flakyKnown) Save(macs []unifi.MAC) error {
+	k.saves = append(k.saves, macs)
+
+	return nil
+}
+
+// titles records the titles and bodies notified.
+type titles []string
+
+func (t *titles) Notify(_ context.Context, msg notify.Message) error {
+	*t = append(*t, msg.Title+": "+msg.Body)
+
+	return nil
+}
+
+func TestMonitorKnownLoadFails(t *testing.T) {
+	known := &flakyKnown{stored: []unifi.MAC{"aa:aa:aa:aa:aa:aa", "cc:cc:cc:cc:cc:cc"}}
+
+	var notified titles
+
+	m := &Monitor{Notifier: &notified, AlertUnknown: true, Known: known}
+
+	a := unifi.Client{Name: "a", MAC: "aa:aa:aa:aa:aa:aa"}
+	b := unifi.Client{Name: "b", MAC: "bb:bb:bb:bb:bb:bb"}
+	c := unifi.Client{Name: "c", MAC: "cc:cc:cc:cc:cc:cc"}
+
+	if err := m.Observe(context.Background(), []unifi.Client{a, b}); err == nil {
+		t.Fatal("got no error from a failed load")
+	}
+
+	if len(known.saves) != 0 {
+		t.Fatalf("saved %v after a failed load", known.saves)
+	}
+
+	if len(notified) != 0 {
+		t.Fatalf("notified %v without a known set", notified)
+	}
+
+	// c was offline during the failed load; it is known, b is not.
+	if err := m.Observe(context.Background(), []unifi.Client{a, b, c}); err != nil {
+		t.Fatal(err)
+	}
+
+	if known.loads != 2 {
+		t.Errorf("loaded %d times, want 2", known.loads)
+	}
+
+	if len(notified) != 0 {
+		t.Errorf("notified %v, want nothing yet: b was already connected", notified)
+	}
+
+	if err := m.Observe(context.Background(), []unifi.Client{a, c}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Observe(context.Background(), []unifi.Client{a, b, c}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(notified) != 1 || notified[0] != "unknown device: b (bb:bb:bb:bb:bb:bb, ) joined the network" {
+		t.Errorf("notified %v, want only b as unknown", notified)
+	}
+
+	if len(known.saves) != 1 || !slices.Contains(known.saves[0], c.MAC) || len(known.saves[0]) != 3 {
+		t.Errorf("saved %v, want the stored set plus b", known.saves)
+	}
+}
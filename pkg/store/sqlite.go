@@ -0,0 +1,199 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS events (
+	id        TEXT PRIMARY KEY,
+	type      TEXT NOT NULL,
+	mac       TEXT NOT NULL,
+	timestamp INTEGER NOT NULL,
+	message   TEXT NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS events_mac ON events (mac, timestamp);
+CREATE INDEX IF NOT EXISTS events_timestamp ON events (timestamp);
+
+CREATE TABLE IF NOT EXISTS clients (
+	seen_at  INTEGER NOT NULL,
+	mac      TEXT NOT NULL,
+	name     TEXT NOT NULL,
+	hostname TEXT NOT NULL,
+	ip       TEXT NOT NULL,
+	network  TEXT NOT NULL,
+	essid    TEXT NOT NULL,
+	is_wired INTEGER NOT NULL,
+	is_guest INTEGER NOT NULL,
+	data     TEXT NOT NULL,
+	PRIMARY KEY (seen_at, mac)
+);
+CREATE INDEX IF NOT EXISTS clients_mac ON clients (mac, seen_at);
+
+CREATE TABLE IF NOT EXISTS snapshots (
+	key        TEXT PRIMARY KEY,
+	updated_at INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+`
+
+// SQLite is a Store backed by a SQLite database file. Timestamps are stored
+// as unix seconds.
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens, creating if needed, the SQLite database at path.
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite %q: %w", path, err)
+	}
+
+	// SQLite allows a single writer; serialize access rather than fail
+	// with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.Exec(sqliteSchema); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("creating sqlite schema: %w", err)
+	}
+
+	return &SQLite{db: db}, nil
+}
+
+func (s *SQLite) PutSnapshot(ctx context.Context, key string, val any) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Errorf("marshalling snapshot %q: %w", key, err)
+	}
+
+	if _, err = s.db.ExecContext(ctx,
+		`INSERT INTO snapshots (key, updated_at, data) VALUES (?, ?, ?)
+		 ON CONFLICT (key) DO UPDATE SET updated_at = excluded.updated_at, data = excluded.data`,
+		key, time.Now().Unix(), string(data)); err != nil {
+		return fmt.Errorf("storing snapshot %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *SQLite) GetSnapshot(ctx context.Context, key string, into any) error {
+	var data string
+
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM snapshots WHERE key = ?`, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("snapshot %q: %w", key, ErrNotFound)
+	}
+
+	if err != nil {
+		return fmt.Errorf("reading snapshot %q: %w", key, err)
+	}
+
+	if err = json.Unmarshal([]byte(data), into); err != nil {
+		return fmt.Errorf("unmarshalling snapshot %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *SQLite) PutClients(ctx context.Context, at time.Time, clients []unifi.Client) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx,
+			`INSERT OR REPLACE INTO clients
+			 (seen_at, mac, name, hostname, ip, network, essid, is_wired, is_guest, data)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, c := range clients {
+			data, err := json.Marshal(c)
+			if err != nil {
+				return fmt.Errorf("marshalling client %q: %w", c.MAC, err)
+			}
+
+			if _, err = stmt.ExecContext(ctx, at.Unix(), c.MAC.String(), c.Name, c.Hostname,
+				c.DisplayIP(unifi.DisplayOptions{}), c.Network, c.ESSID, c.IsWired, c.IsGuest, string(data)); err != nil {
+				return fmt.Errorf("storing client %q: %w", c.MAC, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLite) AppendEvents(ctx context.Context, events []unifi.Event) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx,
+			`INSERT OR IGNORE INTO events (id, type, mac, timestamp, message, data)
+			 VALUES (?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, e := range events {
+			if e.ID == "" {
+				continue
+			}
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				return fmt.Errorf("marshalling event %q: %w", e.ID, err)
+			}
+
+			if _, err = stmt.ExecContext(ctx, e.ID, string(e.Key), eventMAC(e).String(),
+				e.DateTime.Unix(), e.Message, string(data)); err != nil {
+				return fmt.Errorf("storing event %q: %w", e.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLite) Close() error { return s.db.Close() }
+
+func (s *SQLite) tx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// eventMAC picks the client the event is about, falling back to the
+// event's own MAC.
+func eventMAC(e unifi.Event) unifi.MAC {
+	for _, mac := range []unifi.MAC{e.User, e.Guest, e.Client, e.MAC} {
+		if mac != "" {
+			return mac
+		}
+	}
+
+	return ""
+}
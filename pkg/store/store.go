@@ -0,0 +1,46 @@
+// Package store persists agent snapshots and events locally, as an
+// alternative to NATS.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+// ErrNotFound is returned when a snapshot has not been stored yet.
+var ErrNotFound = errors.New("not found")
+
+// Store persists snapshots and events.
+type Store interface {
+	// PutSnapshot replaces the snapshot stored under key.
+	PutSnapshot(ctx context.Context, key string, val any) error
+	// GetSnapshot unmarshals the snapshot stored under key into into.
+	GetSnapshot(ctx context.Context, key string, into any) error
+	// PutClients records the clients seen at a point in time.
+	PutClients(ctx context.Context, at time.Time, clients []unifi.Client) error
+	// AppendEvents records events, ignoring any already recorded.
+	AppendEvents(ctx context.Context, events []unifi.Event) error
+
+	Close() error
+}
+
+// Open opens a store described by spec, in the form "<kind>:<location>",
+// e.g. "sqlite:history.db".
+func Open(spec string) (Store, error) {
+	kind, location, ok := strings.Cut(spec, ":")
+	if !ok || location == "" {
+		return nil, fmt.Errorf("invalid store %q: expected <kind>:<location>", spec)
+	}
+
+	switch kind {
+	case "sqlite":
+		return OpenSQLite(location)
+	default:
+		return nil, fmt.Errorf("unsupported store kind %q", kind)
+	}
+}
@@ -0,0 +1,101 @@
+// Package tlsscan probes which TLS versions and cipher suites a server
+// accepts.
+package tlsscan
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"slices"
+	"time"
+)
+
+// Versions lists the TLS versions probed, oldest first.
+var Versions = []uint16{tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13}
+
+// VersionSupport reports whether the server accepts a TLS version, and
+// which cipher suites it accepts with it.
+type VersionSupport struct {
+	Version   string   `json:"version"`
+	Supported bool     `json:"supported"`
+	Ciphers   []string `json:"ciphers,omitempty"`
+}
+
+// Scan attempts handshakes with addr (host:port) for each TLS version, and
+// for TLS 1.2 and earlier with each cipher suite Go implements. TLS 1.3
+// cipher suites can't be chosen by the client, so only the one negotiated
+// is reported. Certificates are not verified; each handshake is bounded by
+// timeout.
+func Scan(ctx context.Context, addr string, timeout time.Duration) ([]VersionSupport, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphers := append(tls.CipherSuites(), tls.InsecureCipherSuites()...)
+	results := make([]VersionSupport, 0, len(Versions))
+
+	for _, version := range Versions {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		support := VersionSupport{Version: tls.VersionName(version)}
+
+		if version == tls.VersionTLS13 {
+			if state, ok := handshake(ctx, addr, timeout, config(host, version)); ok {
+				support.Supported = true
+				support.Ciphers = []string{tls.CipherSuiteName(state.CipherSuite)}
+			}
+
+			results = append(results, support)
+
+			continue
+		}
+
+		for _, cipher := range ciphers {
+			if !slices.Contains(cipher.SupportedVersions, version) {
+				continue
+			}
+
+			cfg := config(host, version)
+			cfg.CipherSuites = []uint16{cipher.ID}
+
+			if _, ok := handshake(ctx, addr, timeout, cfg); ok {
+				support.Supported = true
+				support.Ciphers = append(support.Ciphers, cipher.Name)
+			}
+		}
+
+		results = append(results, support)
+	}
+
+	return results, nil
+}
+
+func config(host string, version uint16) *tls.Config {
+	return &tls.Config{ // nolint: gosec // probing, not trusting, the server
+		ServerName:         host,
+		InsecureSkipVerify: true,
+		MinVersion:         version,
+		MaxVersion:         version,
+	}
+}
+
+func handshake(ctx context.Context, addr string, timeout time.Duration, cfg *tls.Config) (tls.ConnectionState, bool) {
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: timeout}, Config: cfg}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return tls.ConnectionState{}, false
+	}
+
+	defer conn.Close()
+
+	tlsConn, _ := conn.(*tls.Conn)
+
+	return tlsConn.ConnectionState(), true
+}
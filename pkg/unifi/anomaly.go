@@ -0,0 +1,100 @@
+package unifi
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Anomaly kinds.
+const (
+	AnomalyDevice = "device"
+	AnomalyPort   = "port"
+	AnomalyClient = "client"
+)
+
+// Anomaly is a nonzero anomaly count reported by a device, a switch port,
+// or a client.
+type Anomaly struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	MAC   MAC    `json:"mac"`
+	Port  int64  `json:"port,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// Key identifies the source of the anomaly, e.g. "port/aa:bb:cc:dd:ee:ff/3".
+func (a Anomaly) Key() string {
+	if a.Kind == AnomalyPort {
+		return fmt.Sprintf("%s/%s/%d", a.Kind, a.MAC, a.Port)
+	}
+
+	return fmt.Sprintf("%s/%s", a.Kind, a.MAC)
+}
+
+// Source describes where the anomaly was reported, e.g. "office-switch port 3".
+func (a Anomaly) Source() string {
+	if a.Kind == AnomalyPort {
+		return fmt.Sprintf("%s port %d", a.Name, a.Port)
+	}
+
+	return a.Name
+}
+
+func (a Anomaly) String() string {
+	return fmt.Sprintf("%-6s %-35s %-17s %d", a.Kind, a.Source(), a.MAC, a.Count)
+}
+
+// GetAnomalies returns the nonzero anomaly counts of the devices, their
+// ports, and the connected clients, highest first.
+func (s *Session) GetAnomalies() ([]Anomaly, error) {
+	devices, err := s.GetDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.GetClients()
+	if err != nil {
+		return nil, err
+	}
+
+	anomalies := append(DeviceAnomalies(devices), ClientAnomalies(clients)...)
+
+	sort.SliceStable(anomalies, func(i, j int) bool { return anomalies[i].Count > anomalies[j].Count })
+
+	return anomalies, nil
+}
+
+// DeviceAnomalies returns the nonzero anomaly counts of the devices and
+// their ports.
+func DeviceAnomalies(devices []Device) []Anomaly {
+	var anomalies []Anomaly
+
+	for _, d := range devices {
+		if d.Anomalies > 0 {
+			anomalies = append(anomalies, Anomaly{Kind: AnomalyDevice, Name: d.Name, MAC: d.MAC, Count: d.Anomalies})
+		}
+
+		for _, p := range d.PortTable {
+			if p.Anomalies > 0 {
+				anomalies = append(anomalies,
+					Anomaly{Kind: AnomalyPort, Name: d.Name, MAC: d.MAC, Port: p.PortIndex, Count: p.Anomalies})
+			}
+		}
+	}
+
+	return anomalies
+}
+
+// ClientAnomalies returns the nonzero anomaly counts of the clients.
+func ClientAnomalies(clients []Client) []Anomaly {
+	var anomalies []Anomaly
+
+	for ix := range clients {
+		c := &clients[ix]
+		if c.Anomalies > 0 {
+			anomalies = append(anomalies, Anomaly{Kind: AnomalyClient, Name: c.DisplayName(), MAC: c.MAC, Count: c.Anomalies})
+		}
+	}
+
+	return anomalies
+}
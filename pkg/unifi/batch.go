@@ -0,0 +1,150 @@
+package unifi
+
+import "fmt"
+
+// ActionResult is the outcome of an action on one client. Unchanged is set
+// when the client was already in the desired state, so nothing was sent.
+type ActionResult struct {
+	MAC       MAC    `json:"mac"`
+	Name      string `json:"name,omitempty"`
+	OK        bool   `json:"ok"`
+	Unchanged bool   `json:"unchanged,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BatchResult is the outcome of an action on several clients. Unmatched
+// holds the requested names that matched no client.
+type BatchResult struct {
+	Action    string         `json:"action"`
+	Affected  []ActionResult `json:"affected"`
+	Unmatched []string       `json:"unmatched,omitempty"`
+}
+
+// BlockByName resolves names (or IPs, or MACs) to clients and blocks them
+// in a single call.
+func (s *Session) BlockByName(names ...string) (BatchResult, error) {
+	return s.byName("block", s.Block, names)
+}
+
+// UnblockByName resolves names (or IPs, or MACs) to clients and unblocks
+// them in a single call.
+func (s *Session) UnblockByName(names ...string) (BatchResult, error) {
+	return s.byName("unblock", s.Unblock, names)
+}
+
+func (s *Session) byName(action string, fn func(...MAC) (string, error), names []string) (BatchResult, error) {
+	result := BatchResult{Action: action}
+
+	matched, err := s.GetMACsByID(names...)
+	if err != nil {
+		return result, err
+	}
+
+	seen := map[MAC]bool{}
+
+	for _, name := range names {
+		if len(matched[name]) == 0 {
+			result.Unmatched = append(result.Unmatched, name)
+
+			continue
+		}
+
+		for _, mac := range matched[name] {
+			if !seen[mac] {
+				seen[mac] = true
+				result.Affected = append(result.Affected, ActionResult{MAC: mac, Name: name})
+			}
+		}
+	}
+
+	return s.ApplyBatch(result, fn)
+}
+
+// WithSkipIfSatisfied makes block and unblock batches first look up which
+// clients are already blocked or unblocked, and leave those out of the
+// request, reporting them as unchanged.
+func WithSkipIfSatisfied(skip bool) Option { return func(s *Session) { s.skipIfSatisfied = skip } }
+
+// ApplyBatch applies fn as the ApplyBatch function does, first marking the
+// clients already in the desired state as unchanged when the session was
+// created WithSkipIfSatisfied.
+func (s *Session) ApplyBatch(result BatchResult, fn func(...MAC) (string, error)) (BatchResult, error) {
+	if s.skipIfSatisfied {
+		result = s.markUnchanged(result)
+	}
+
+	return ApplyBatch(result, fn)
+}
+
+// markUnchanged marks the clients of a block or unblock batch that are
+// already blocked or unblocked. If the clients can't be fetched a warning
+// is written and all are acted on.
+func (s *Session) markUnchanged(result BatchResult) BatchResult {
+	var blocked bool
+
+	switch result.Action {
+	case "block":
+		blocked = true
+	case "unblock":
+		blocked = false
+	default:
+		return result
+	}
+
+	// Blocked clients are usually not connected, so look at all known ones.
+	clients, err := s.GetAllClients()
+	if err != nil {
+		fmt.Fprintf(s.errWriter, "warning: checking current block state: %v\n", err)
+
+		return result
+	}
+
+	state := map[MAC]bool{}
+	for _, c := range clients {
+		state[normalMAC(c.MAC)] = c.IsBlocked
+	}
+
+	for ix, affected := range result.Affected {
+		if current, ok := state[normalMAC(affected.MAC)]; ok && current == blocked {
+			result.Affected[ix].Unchanged = true
+		}
+	}
+
+	return result
+}
+
+// ApplyBatch applies fn, e.g. Session.Block, to the MACs of the result's
+// affected clients in one call, and marks each with the outcome. Clients
+// already marked unchanged are left out of the call and count as OK.
+func ApplyBatch(result BatchResult, fn func(...MAC) (string, error)) (BatchResult, error) {
+	var macs []MAC
+
+	for ix, affected := range result.Affected {
+		if affected.Unchanged {
+			result.Affected[ix].OK = true
+
+			continue
+		}
+
+		macs = append(macs, affected.MAC)
+	}
+
+	if len(macs) == 0 {
+		return result, nil
+	}
+
+	_, err := fn(macs...)
+
+	for ix := range result.Affected {
+		if result.Affected[ix].Unchanged {
+			continue
+		}
+
+		result.Affected[ix].OK = err == nil
+		if err != nil {
+			result.Affected[ix].Error = err.Error()
+		}
+	}
+
+	return result, err
+}
@@ -0,0 +1,87 @@
+package unifi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CEF renders the event in ArcSight Common Event Format, with the event
+// key as the signature ID and version as the device version:
+//
+//	CEF:0|Ubiquiti|UniFi|version|EVT_...|name|severity|extensions
+//
+// The client is the destination (dmac, dhost, src is its IP) and the UniFi
+// device reporting the event is the device (dvcmac, dvchost).
+func (e Event) CEF(version string) string {
+	name := e.Message
+	if name == "" {
+		name = string(e.Key)
+	}
+
+	var ext []string
+
+	add := func(key, val string) {
+		if val != "" {
+			ext = append(ext, key+"="+cefExtension(val))
+		}
+	}
+
+	if !e.DateTime.IsZero() {
+		add("rt", strconv.FormatInt(e.DateTime.UnixMilli(), 10))
+	}
+
+	add("externalId", e.ID)
+	add("src", string(e.IP))
+	add("dmac", firstNonEmpty(string(e.User), string(e.Guest), string(e.Client), string(e.MAC)))
+	add("dhost", e.Hostname)
+	add("dvcmac", firstNonEmpty(string(e.AccessPoint), string(e.Switch), string(e.Gateway), string(e.Bridge), string(e.DM)))
+	add("dvchost", firstNonEmpty(e.AccessPointName, e.SwitchName, e.BridgeName, e.DMName,
+		e.AccessPointDisplay, e.SwitchDisplay, e.GatewayDisplay, e.BridgeDisplay, e.DMDisplay))
+	add("suser", e.Admin)
+
+	if ssid := firstNonEmpty(e.SSID, e.ESSID); ssid != "" {
+		add("cs1Label", "SSID")
+		add("cs1", ssid)
+	}
+
+	if e.Subsystem != "" {
+		add("cs2Label", "Subsystem")
+		add("cs2", e.Subsystem)
+	}
+
+	if e.SiteID != "" {
+		add("cs3Label", "Site")
+		add("cs3", e.SiteID)
+	}
+
+	if e.VLAN > 0 {
+		add("cn1Label", "VLAN")
+		add("cn1", strconv.FormatInt(e.VLAN, 10))
+	}
+
+	add("msg", e.Message)
+
+	return fmt.Sprintf("CEF:0|Ubiquiti|UniFi|%s|%s|%s|%d|%s",
+		cefHeader(version), cefHeader(string(e.Key)), cefHeader(name), e.Severity().CEF(), strings.Join(ext, " "))
+}
+
+// CEF returns the severity on the CEF scale of 0 to 10.
+func (s EventSeverity) CEF() int {
+	switch s {
+	case SeverityCritical:
+		return 9
+	case SeverityWarning:
+		return 6
+	default:
+		return 3
+	}
+}
+
+func cefHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ").Replace(s)
+}
+
+func cefExtension(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
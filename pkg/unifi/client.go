@@ -1,32 +1,33 @@
 package unifi
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
-	"sort"
+	"slices"
+	"strings"
 	"time"
-
-	"github.com/dustin/go-humanize"
 )
 
 var (
-	ClientBytesReceived = func(lhs, rhs *Client) bool { return lhs.BytesReceived < rhs.BytesReceived }
-	ClientBytesSent     = func(lhs, rhs *Client) bool { return lhs.BytesSent < rhs.BytesSent }
-	ClientConfidence    = func(lhs, rhs *Client) bool { return lhs.Confidence < rhs.Confidence }
-	ClientFirstSeen     = func(lhs, rhs *Client) bool { return lhs.FirstSeen < rhs.FirstSeen }
-	ClientIP            = func(lhs, rhs *Client) bool { return lhs.IP.Less(rhs.IP) }
-	ClientIdle          = func(lhs, rhs *Client) bool { return lhs.IdleTime < rhs.IdleTime }
-	ClientAuthorized    = func(lhs, rhs *Client) bool { return !lhs.IsAuthorized && rhs.IsAuthorized }
-	ClientBlocked       = func(lhs, rhs *Client) bool { return !lhs.IsBlocked && rhs.IsBlocked }
-	ClientGuest         = func(lhs, rhs *Client) bool { return !lhs.IsGuest && rhs.IsGuest }
-	ClientWired         = func(lhs, rhs *Client) bool { return lhs.IsWired && !rhs.IsWired }
-	ClientLastSeen      = func(lhs, rhs *Client) bool { return lhs.LastSeen < rhs.LastSeen }
-	ClientName          = func(lhs, rhs *Client) bool { return lhs.DisplayName() < rhs.DisplayName() }
-	ClientNetwork       = func(lhs, rhs *Client) bool { return lhs.Network < rhs.Network }
-	ClientNoise         = func(lhs, rhs *Client) bool { return lhs.Noise < rhs.Noise }
-	ClientSatisfaction  = func(lhs, rhs *Client) bool { return lhs.Satisfaction < rhs.Satisfaction }
-	ClientScore         = func(lhs, rhs *Client) bool { return lhs.Score < rhs.Score }
-	ClientSignal        = func(lhs, rhs *Client) bool { return lhs.Signal < rhs.Signal }
-	ClientUptime        = func(lhs, rhs *Client) bool { return lhs.Uptime < rhs.Uptime }
+	ClientBytesReceived = func(c *Client) SortKey { return SortKey{Num: c.BytesReceived} }
+	ClientBytesSent     = func(c *Client) SortKey { return SortKey{Num: c.BytesSent} }
+	ClientConfidence    = func(c *Client) SortKey { return SortKey{Num: c.Confidence} }
+	ClientFirstSeen     = func(c *Client) SortKey { return SortKey{Num: int64(c.FirstSeen)} }
+	ClientIP            = func(c *Client) SortKey { return SortKey{Str: c.IP.sortKey()} }
+	ClientIdle          = func(c *Client) SortKey { return SortKey{Num: c.IdleTime} }
+	ClientAuthorized    = func(c *Client) SortKey { return SortKey{Num: boolKey(c.IsAuthorized)} }
+	ClientBlocked       = func(c *Client) SortKey { return SortKey{Num: boolKey(c.IsBlocked)} }
+	ClientGuest         = func(c *Client) SortKey { return SortKey{Num: boolKey(c.IsGuest)} }
+	ClientWired         = func(c *Client) SortKey { return SortKey{Num: boolKey(!c.IsWired)} }
+	ClientLastSeen      = func(c *Client) SortKey { return SortKey{Num: int64(c.LastSeen)} }
+	ClientName          = func(c *Client) SortKey { return SortKey{Str: c.DisplayName()} }
+	ClientNetwork       = func(c *Client) SortKey { return SortKey{Str: c.Network} }
+	ClientNoise         = func(c *Client) SortKey { return SortKey{Num: c.Noise} }
+	ClientSatisfaction  = func(c *Client) SortKey { return SortKey{Num: c.Satisfaction} }
+	ClientScore         = func(c *Client) SortKey { return SortKey{Num: c.Score} }
+	ClientSignal        = func(c *Client) SortKey { return SortKey{Num: c.Signal} }
+	ClientUptime        = func(c *Client) SortKey { return SortKey{Num: c.Uptime} }
 
 	ClientDefault    = ClientOrderedBy(ClientWired, ClientIP)
 	ClientHistorical = ClientOrderedBy(ClientLastSeen)
@@ -38,98 +39,114 @@ var (
 type Client struct {
 	ID string `json:"_id,omitempty"`
 
-	AccessPointMAC           string  `json:"ap_mac,omitempty"`
-	Anomalies                int64   `json:"anomalies,omitempty"`
-	BSSID                    string  `json:"bssid,omitempty"`
-	BytesError               float64 `json:"bytes-r,omitempty"`
-	BytesReceived            int64   `json:"rx_bytes,omitempty"`
-	BytesReceivedError       float64 `json:"rx_bytes-r,omitempty"`
-	BytesSent                int64   `json:"tx_bytes,omitempty"`
-	BytesSentError           float64 `json:"tx_bytes-r,omitempty"`
-	CCQ                      int64   `json:"ccq,omitempty"`
-	Channel                  int64   `json:"channel,omitempty"`
-	Confidence               int64   `json:"confidence,omitempty"`
-	DHCPEndTime              int64   `json:"dhcpend_time,omitempty"`
-	DeviceCategory           int64   `json:"dev_cat,omitempty"`
-	DeviceFamily             int64   `json:"dev_family,omitempty"`
-	DeviceID                 int64   `json:"dev_id,omitempty"`
-	DeviceIDOverride         int64   `json:"dev_id_override,omitempty"`
-	DeviceName               string  `json:"device_name,omitempty"`
-	DeviceVendor             int64   `json:"dev_vendor,omitempty"`
-	ESSID                    string  `json:"essid,omitempty"`
-	FingerprintEngineVersion string  `json:"fingerprint_engine_version,omitempty"`
-	FingerprintSource        int64   `json:"fingerprint_source,omitempty"`
-	FirmwareVersion          string  `json:"fw_version,omitempty"`
-	FirstAssociatedAt        int64   `json:"assoc_time,omitempty"`
-	FirstSeen                int64   `json:"first_seen,omitempty"`
-	FixedIP                  IP      `json:"fixed_ip,omitempty"`
-	GatewayMAC               string  `json:"gw_mac,omitempty"`
-	HasFingerprintOverride   bool    `json:"fingerprint_override,omitempty"`
-	HasQosApplied            bool    `json:"qos_policy_applied,omitempty"`
-	Hostname                 string  `json:"hostname,omitempty"`
-	IP                       IP      `json:"ip,omitempty"`
-	IdleTime                 int64   `json:"idletime,omitempty"`
-	Is11r                    bool    `json:"is_11r,omitempty"`
-	IsAuthorized             bool    `json:"authorized,omitempty"`
-	IsBlocked                bool    `json:"blocked,omitempty"`
-	IsGuest                  bool    `json:"is_guest,omitempty"`
-	IsNoted                  bool    `json:"noted,omitempty"`
-	IsPowersaveEnabled       bool    `json:"powersave_enabled,omitempty"`
-	IsUAPGuest               bool    `json:"_is_guest_by_uap,omitempty"`
-	IsUGWGuest               bool    `json:"_is_guest_by_ugw,omitempty"`
-	IsUSWGuest               bool    `json:"_is_guest_by_usw,omitempty"`
-	IsWired                  bool    `json:"is_wired,omitempty"`
-	LastAssociatedAt         int64   `json:"latest_assoc_time,omitempty"`
-	LastSeen                 int64   `json:"last_seen,omitempty"`
-	MAC                      MAC     `json:"mac,omitempty"`
-	Name                     string  `json:"name,omitempty"`
-	Network                  string  `json:"network,omitempty"`
-	NetworkID                string  `json:"network_id,omitempty"`
-	Noise                    int64   `json:"noise,omitempty"`
-	Note                     string  `json:"note,omitempty"`
-	OSName                   int64   `json:"os_name,omitempty"`
-	OUI                      string  `json:"oui,omitempty"`
-	PacketsReceived          int64   `json:"rx_packets,omitempty"`
-	PacketsSent              int64   `json:"tx_packets,omitempty"`
-	RSSI                     int64   `json:"rssi,omitempty"`
-	Radio                    string  `json:"radio,omitempty"`
-	RadioName                string  `json:"radio_name,omitempty"`
-	RadioProto               string  `json:"radio_proto,omitempty"`
-	ReceiveRate              int64   `json:"rx_rate,omitempty"`
-	Retries                  int64   `json:"tx_retries,omitempty"`
-	Satisfaction             int64   `json:"satisfaction,omitempty"`
-	Score                    int64   `json:"score,omitempty"`
-	Signal                   int64   `json:"signal,omitempty"`
-	SiteID                   string  `json:"site_id,omitempty"`
-	SwitchDepth              int64   `json:"sw_depth,omitempty"`
-	SwitchMAC                string  `json:"sw_mac,omitempty"`
-	SwitchPort               int64   `json:"sw_port,omitempty"`
-	TransmitPower            int64   `json:"tx_power,omitempty"`
-	TransmitRate             int64   `json:"tx_rate,omitempty"`
-	UAPLastSeen              int64   `json:"_last_seen_by_uap,omitempty"`
-	UAPUptime                int64   `json:"_uptime_by_uap,omitempty"`
-	UGWLastSeen              int64   `json:"_last_seen_by_ugw,omitempty"`
-	UGWUptime                int64   `json:"_uptime_by_ugw,omitempty"`
-	USWLastSeen              int64   `json:"_last_seen_by_usw,omitempty"`
-	USWUptime                int64   `json:"_uptime_by_usw,omitempty"`
-	Uptime                   int64   `json:"uptime,omitempty"`
-	UseFixedIP               bool    `json:"use_fixedip,omitempty"`
-	UserGroupIDComputed      string  `json:"user_group_id_computed,omitempty"`
-	UserID                   string  `json:"user_id,omitempty"`
-	UsergroupID              string  `json:"usergroup_id,omitempty"`
-	VLAN                     int64   `json:"vlan,omitempty"`
-	WifiAttempts             int64   `json:"wifi_tx_attempts,omitempty"`
-	WiredBytesReceived       int64   `json:"wired-rx_bytes,omitempty"`
-	WiredBytesReceivedError  float64 `json:"wired-rx_bytes-r,omitempty"`
-	WiredBytesSent           int64   `json:"wired-tx_bytes,omitempty"`
-	WiredBytesSentError      float64 `json:"wired-tx_bytes-r,omitempty"`
-	WiredPacketsReceived     int64   `json:"wired-rx_packets,omitempty"`
-	WiredPacketsSent         int64   `json:"wired-tx_packets,omitempty"`
-	WiredRateMBPS            int64   `json:"wired_rate_mbps,omitempty"`
+	AccessPointMAC           string    `json:"ap_mac,omitempty"`
+	Anomalies                int64     `json:"anomalies,omitempty"`
+	BSSID                    string    `json:"bssid,omitempty"`
+	BytesError               float64   `json:"bytes-r,omitempty"`
+	BytesReceived            int64     `json:"rx_bytes,omitempty"`
+	BytesReceivedError       float64   `json:"rx_bytes-r,omitempty"`
+	BytesSent                int64     `json:"tx_bytes,omitempty"`
+	BytesSentError           float64   `json:"tx_bytes-r,omitempty"`
+	CCQ                      int64     `json:"ccq,omitempty"`
+	Channel                  int64     `json:"channel,omitempty"`
+	Confidence               int64     `json:"confidence,omitempty"`
+	DHCPEndTime              TimeStamp `json:"dhcpend_time,omitempty"`
+	DeviceCategory           int64     `json:"dev_cat,omitempty"`
+	DeviceFamily             int64     `json:"dev_family,omitempty"`
+	DeviceID                 int64     `json:"dev_id,omitempty"`
+	DeviceIDOverride         int64     `json:"dev_id_override,omitempty"`
+	DeviceName               string    `json:"device_name,omitempty"`
+	DeviceVendor             int64     `json:"dev_vendor,omitempty"`
+	ESSID                    string    `json:"essid,omitempty"`
+	FingerprintEngineVersion string    `json:"fingerprint_engine_version,omitempty"`
+	FingerprintSource        int64     `json:"fingerprint_source,omitempty"`
+	FirmwareVersion          string    `json:"fw_version,omitempty"`
+	FirstAssociatedAt        TimeStamp `json:"assoc_time,omitempty"`
+	FirstSeen                TimeStamp `json:"first_seen,omitempty"`
+	FixedIP                  IP        `json:"fixed_ip,omitempty"`
+	GatewayMAC               string    `json:"gw_mac,omitempty"`
+	HasFingerprintOverride   bool      `json:"fingerprint_override,omitempty"`
+	HasQosApplied            bool      `json:"qos_policy_applied,omitempty"`
+	Hostname                 string    `json:"hostname,omitempty"`
+	IP                       IP        `json:"ip,omitempty"`
+	IdleTime                 int64     `json:"idletime,omitempty"`
+	Is11r                    bool      `json:"is_11r,omitempty"`
+	IsAuthorized             bool      `json:"authorized,omitempty"`
+	IsBlocked                bool      `json:"blocked,omitempty"`
+	IsGuest                  bool      `json:"is_guest,omitempty"`
+	IsNoted                  bool      `json:"noted,omitempty"`
+	IsPowersaveEnabled       bool      `json:"powersave_enabled,omitempty"`
+	IsUAPGuest               bool      `json:"_is_guest_by_uap,omitempty"`
+	IsUGWGuest               bool      `json:"_is_guest_by_ugw,omitempty"`
+	IsUSWGuest               bool      `json:"_is_guest_by_usw,omitempty"`
+	IsWired                  bool      `json:"is_wired,omitempty"`
+	LastAssociatedAt         TimeStamp `json:"latest_assoc_time,omitempty"`
+	LastSeen                 TimeStamp `json:"last_seen,omitempty"`
+	MAC                      MAC       `json:"mac,omitempty"`
+	Name                     string    `json:"name,omitempty"`
+	Network                  string    `json:"network,omitempty"`
+	NetworkID                string    `json:"network_id,omitempty"`
+	Noise                    int64     `json:"noise,omitempty"`
+	Note                     string    `json:"note,omitempty"`
+	OSName                   int64     `json:"os_name,omitempty"`
+	OUI                      string    `json:"oui,omitempty"`
+	PacketsReceived          int64     `json:"rx_packets,omitempty"`
+	PacketsSent              int64     `json:"tx_packets,omitempty"`
+	RSSI                     int64     `json:"rssi,omitempty"`
+	Radio                    string    `json:"radio,omitempty"`
+	RadioName                string    `json:"radio_name,omitempty"`
+	RadioProto               string    `json:"radio_proto,omitempty"`
+	ReceiveRate              int64     `json:"rx_rate,omitempty"` // Kbps
+	Retries                  int64     `json:"tx_retries,omitempty"`
+	Satisfaction             int64     `json:"satisfaction,omitempty"`
+	Score                    int64     `json:"score,omitempty"`
+	Signal                   int64     `json:"signal,omitempty"`
+	SiteID                   string    `json:"site_id,omitempty"`
+	SwitchDepth              int64     `json:"sw_depth,omitempty"`
+	SwitchMAC                string    `json:"sw_mac,omitempty"`
+	SwitchPort               int64     `json:"sw_port,omitempty"`
+	TransmitPower            int64     `json:"tx_power,omitempty"`
+	TransmitRate             int64     `json:"tx_rate,omitempty"` // Kbps
+	UAPLastSeen              TimeStamp `json:"_last_seen_by_uap,omitempty"`
+	UAPUptime                int64     `json:"_uptime_by_uap,omitempty"`
+	UGWLastSeen              TimeStamp `json:"_last_seen_by_ugw,omitempty"`
+	UGWUptime                int64     `json:"_uptime_by_ugw,omitempty"`
+	USWLastSeen              TimeStamp `json:"_last_seen_by_usw,omitempty"`
+	USWUptime                int64     `json:"_uptime_by_usw,omitempty"`
+	Uptime                   int64     `json:"uptime,omitempty"`
+	UseFixedIP               bool      `json:"use_fixedip,omitempty"`
+	UserGroupIDComputed      string    `json:"user_group_id_computed,omitempty"`
+	UserID                   string    `json:"user_id,omitempty"`
+	UsergroupID              string    `json:"usergroup_id,omitempty"`
+	VLAN                     int64     `json:"vlan,omitempty"`
+	WifiAttempts             int64     `json:"wifi_tx_attempts,omitempty"`
+	WiredBytesReceived       int64     `json:"wired-rx_bytes,omitempty"`
+	WiredBytesReceivedError  float64   `json:"wired-rx_bytes-r,omitempty"`
+	WiredBytesSent           int64     `json:"wired-tx_bytes,omitempty"`
+	WiredBytesSentError      float64   `json:"wired-tx_bytes-r,omitempty"`
+	WiredPacketsReceived     int64     `json:"wired-rx_packets,omitempty"`
+	WiredPacketsSent         int64     `json:"wired-tx_packets,omitempty"`
+	WiredRateMBPS            int64     `json:"wired_rate_mbps,omitempty"`
 
 	// Synthetic fields
 
-	UpstreamName string `json:"upstream_name,omitempty"`
+	UpstreamName string `json:"upstream_name,omitempty" schema:"synthetic"`
+
+	// displayName caches DisplayName; it is set when unmarshalling.
+	displayName string
+}
+
+// UnmarshalJSON decodes the client and caches its DisplayName.
+func (client *Client) UnmarshalJSON(b []byte) error {
+	type plain Client
+
+	if err := json.Unmarshal(b, (*plain)(client)); err != nil {
+		return err
+	}
+
+	client.displayName = client.computeDisplayName()
+
+	return nil
 }
 
 func (client *Client) IsBlockedGlyph() rune {
@@ -156,24 +173,56 @@ func (client *Client) IsWiredGlyph() rune {
 	return '⌔'
 }
 
+// DisplayName returns the first of the name, hostname, device name, OUI,
+// and MAC that is set. It is computed once when the client is unmarshalled,
+// so changing those fields afterwards does not change it; clients built in
+// code compute it on each call.
 func (client *Client) DisplayName() string {
+	if client.displayName != "" {
+		return client.displayName
+	}
+
+	return client.computeDisplayName()
+}
+
+func (client *Client) computeDisplayName() string {
 	return firstNonEmpty(client.Name, client.Hostname, client.DeviceName, client.OUI, string(client.MAC), "-")
 }
 
-func (client *Client) DisplayIP() string {
+// DisplayIP returns the current IP or the fixed IP, whichever is set,
+// preferring the one selected by o.IP.
+func (client *Client) DisplayIP(o DisplayOptions) string {
+	if o.IP == PreferFixedIP {
+		return firstNonEmpty(string(client.FixedIP), string(client.IP))
+	}
+
 	return firstNonEmpty(string(client.IP), string(client.FixedIP))
 }
 
-func (client *Client) DisplayLastAssociated() string {
-	return humanize.Time(time.Unix(client.LastAssociatedAt, 0))
+func (client *Client) DisplayLastAssociated(o DisplayOptions) string {
+	return o.Time(client.LastAssociatedAt.Time())
+}
+
+// DisplayFirstSeen returns when the client was first seen, or "-" if that
+// is unknown.
+func (client *Client) DisplayFirstSeen(o DisplayOptions) string {
+	if client.FirstSeen == 0 {
+		return "-"
+	}
+
+	return o.Time(client.FirstSeen.Time())
 }
 
-func (client *Client) DisplayUptime() string {
+// DisplayUptime returns when the current session started, relative to now,
+// or for a client without uptime when it last associated. Use
+// DisplaySessionDuration and DisplayOfflineDuration to tell a connected
+// client from one last seen a while ago.
+func (client *Client) DisplayUptime(o DisplayOptions) string {
 	if client.Uptime == 0 {
-		return humanize.Time(time.Unix(client.LastAssociatedAt, 0))
+		return o.Time(client.LastAssociatedAt.Time())
 	}
 
-	return humanize.Time(time.Now().Add(time.Duration(client.Uptime) * -time.Second))
+	return o.Time(time.Now().Add(time.Duration(client.Uptime) * -time.Second))
 }
 
 func (client *Client) DisplayReceivedBytes() string {
@@ -198,7 +247,7 @@ func (client *Client) DisplayReceiveRate() string {
 		return rate
 	}
 
-	return formatBytesSize(client.ReceiveRate)
+	return formatRate(client.ReceiveRate * 1000)
 }
 
 func (client *Client) DisplaySendRate() string {
@@ -207,7 +256,7 @@ func (client *Client) DisplaySendRate() string {
 		return rate
 	}
 
-	return formatBytesSize(client.TransmitRate)
+	return formatRate(client.TransmitRate * 1000)
 }
 
 func (client *Client) DisplayWiredRate() string {
@@ -221,7 +270,7 @@ func (client *Client) DisplayWiredRate() string {
 			return "GbE"
 		}
 
-		return humanize.Bytes(uint64(client.WiredRateMBPS * 1000000))
+		return formatRate(client.WiredRateMBPS * 1000000)
 	}
 
 	return ""
@@ -233,7 +282,7 @@ func (client *Client) DisplayConnectionRate() string {
 			return ""
 		}
 
-		return humanize.Bytes(uint64(client.WiredRateMBPS * 1000000))
+		return formatRate(client.WiredRateMBPS * 1000000)
 	}
 
 	return fmt.Sprintf("%11s↓ %11s↑", client.DisplayReceiveRate(), client.DisplaySendRate())
@@ -247,19 +296,30 @@ func (client *Client) DisplaySwitchName() string {
 	return client.UpstreamMAC()
 }
 
-func (client *Client) String() string {
+func (client *Client) String() string { return client.Display(DisplayOptions{}) }
+
+// Display renders the client on one line. It shows how long a connected
+// client has been connected, or how long any other has been offline.
+func (client *Client) Display(o DisplayOptions) string {
 	rate := ""
 	if ShowRate {
 		rate = fmt.Sprintf("%25s", client.DisplayConnectionRate())
 	}
 
-	return fmt.Sprintf("%25s %-2s%-2s%-2s %-15s %-14s %-25s %s %s",
+	session := "-"
+	if _, ok := client.SessionDuration(); ok {
+		session = "connected for " + client.DisplaySessionDuration()
+	} else if _, ok := client.OfflineDuration(); ok {
+		session = "offline for " + client.DisplayOfflineDuration()
+	}
+
+	return fmt.Sprintf("%25s %-2s%-2s%-2s %-15s %-20s %-25s %s %s",
 		client.DisplayName(),
 		string(client.IsBlockedGlyph()),
 		string(client.IsGuestGlyph()),
 		string(client.IsWiredGlyph()),
-		client.DisplayIP(),
-		client.DisplayUptime(),
+		client.DisplayIP(o),
+		session,
 		fmt.Sprintf("%11s↓ %11s↑", client.DisplayReceivedBytes(), client.DisplaySentBytes()),
 		rate,
 		client.DisplaySwitchName(),
@@ -281,39 +341,97 @@ func ToMACs(clients []Client) []MAC {
 	return macs
 }
 
-// ClientOrderedBy returns a ClientSorter that sorts by the provided less functions.
-func ClientOrderedBy(less ...ClientLessFn) *ClientSorter {
-	return &ClientSorter{less: less}
+// ClientOrderedBy returns a ClientSorter that sorts by the provided keys,
+// the first key taking precedence.
+func ClientOrderedBy(keys ...ClientKeyFn) *ClientSorter {
+	return &ClientSorter{keys: keys}
 }
 
-// ClientLessFn describes a less function for a Client.
-type ClientLessFn func(lhs, rhs *Client) bool
+// ClientKeyFn returns the key a Client is sorted by.
+type ClientKeyFn func(*Client) SortKey
+
+// SortKey is a precomputed sort key. Keys compare by Num, then by Str, in
+// ascending order.
+type SortKey struct {
+	Num int64
+	Str string
+}
+
+func (lhs SortKey) compare(rhs SortKey) int {
+	if c := cmp.Compare(lhs.Num, rhs.Num); c != 0 {
+		return c
+	}
+
+	return strings.Compare(lhs.Str, rhs.Str)
+}
 
 // ClientSorter is a multisorter for sorting slices of Client.
 type ClientSorter struct {
-	clients []Client
-	less    []ClientLessFn
+	keys []ClientKeyFn
 }
 
-// Sort applies the configured less functions in order.
+// Sort orders clients by the configured keys.
+//
+// Keys such as DisplayName and IP are costly to derive, so each key is
+// computed once per client up front rather than on every comparison, and
+// as Client is a large struct, each client is copied into its final
+// position once rather than swapped repeatedly. The sorter itself is not
+// modified, so Sort is safe for concurrent use.
 func (s *ClientSorter) Sort(clients []Client) {
-	s.clients = clients
-	sort.Sort(s)
-}
+	type decorated struct {
+		keys []SortKey
+		from int
+	}
+
+	n, k := len(clients), len(s.keys)
+	all := make([]SortKey, n*k)
+	items := make([]decorated, n)
+
+	for ix := range clients {
+		keys := all[ix*k : (ix+1)*k : (ix+1)*k]
+		for kx, key := range s.keys {
+			keys[kx] = key(&clients[ix])
+		}
+
+		items[ix] = decorated{keys: keys, from: ix}
+	}
 
-func (s *ClientSorter) Len() int      { return len(s.clients) }
-func (s *ClientSorter) Swap(i, j int) { s.clients[i], s.clients[j] = s.clients[j], s.clients[i] }
-func (s *ClientSorter) Less(i, j int) bool {
-	lhs, rhs := &s.clients[i], &s.clients[j]
-	var k int
-	for k = 0; k < len(s.less)-1; k++ {
-		less := s.less[k]
-		switch {
-		case less(lhs, rhs):
-			return true
-		case less(rhs, lhs):
-			return false
+	slices.SortFunc(items, func(lhs, rhs decorated) int {
+		for kx := range lhs.keys {
+			if c := lhs.keys[kx].compare(rhs.keys[kx]); c != 0 {
+				return c
+			}
 		}
+
+		return 0
+	})
+
+	// Apply the permutation in place, one cycle at a time, so that each
+	// client is moved once.
+	for start := range items {
+		if items[start].from < 0 {
+			continue
+		}
+
+		held := clients[start]
+
+		ix := start
+		for items[ix].from != start {
+			from := items[ix].from
+			clients[ix] = clients[from]
+			items[ix].from = -1
+			ix = from
+		}
+
+		clients[ix] = held
+		items[ix].from = -1
 	}
-	return s.less[k](lhs, rhs)
+}
+
+func boolKey(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
 }
@@ -0,0 +1,167 @@
+package unifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestClientSorter(t *testing.T) {
+	clients := []Client{
+		{MAC: "aa:00:00:00:00:01", IP: "192.168.1.20"},
+		{MAC: "aa:00:00:00:00:02", IP: "192.168.1.3", IsWired: true},
+		{MAC: "aa:00:00:00:00:03"},
+		{MAC: "aa:00:00:00:00:04", IP: "10.0.0.1"},
+		{MAC: "aa:00:00:00:00:05", IP: "192.168.1.100", IsWired: true},
+		{MAC: "aa:00:00:00:00:06", IP: "not-an-ip"},
+	}
+
+	ClientDefault.Sort(clients)
+
+	want := []MAC{
+		"aa:00:00:00:00:02", "aa:00:00:00:00:05", // wired, by IP
+		"aa:00:00:00:00:03", "aa:00:00:00:00:06", // no IP, then unparseable
+		"aa:00:00:00:00:04", "aa:00:00:00:00:01",
+	}
+
+	for ix, c := range clients {
+		if c.MAC != want[ix] {
+			t.Errorf("position %d: got %s, want %s", ix, c.MAC, want[ix])
+		}
+	}
+}
+
+func TestClientSorterMatchesIPLess(t *testing.T) {
+	clients := benchmarkClients(500)
+	ClientOrderedBy(ClientIP).Sort(clients)
+
+	for ix := 1; ix < len(clients); ix++ {
+		if clients[ix].IP.Less(clients[ix-1].IP) {
+			t.Fatalf("position %d: %s sorted after %s", ix, clients[ix].IP, clients[ix-1].IP)
+		}
+	}
+}
+
+// lessSorter is the less-function multisorter ClientSorter replaced, kept
+// as the baseline for BenchmarkClientSorter.
+type lessSorter struct {
+	clients []Client
+	less    []func(lhs, rhs *Client) bool
+}
+
+func (s *lessSorter) Len() int      { return len(s.clients) }
+func (s *lessSorter) Swap(i, j int) { s.clients[i], s.clients[j] = s.clients[j], s.clients[i] }
+func (s *lessSorter) Less(i, j int) bool {
+	lhs, rhs := &s.clients[i], &s.clients[j]
+
+	var k int
+	for k = 0; k < len(s.less)-1; k++ {
+		switch {
+		case s.less[k](lhs, rhs):
+			return true
+		case s.less[k](rhs, lhs):
+			return false
+		}
+	}
+
+	return s.less[k](lhs, rhs)
+}
+
+func benchmarkClients(n int) []Client {
+	r := rand.New(rand.NewSource(1))
+
+	clients := make([]Client, n)
+	for ix := range clients {
+		clients[ix] = Client{
+			MAC:      MAC(fmt.Sprintf("aa:bb:cc:%02x:%02x:%02x", ix>>16&0xff, ix>>8&0xff, ix&0xff)),
+			IP:       IP(fmt.Sprintf("10.%d.%d.%d", r.Intn(4), r.Intn(256), r.Intn(256))),
+			Hostname: fmt.Sprintf("host-%d", r.Intn(n)),
+			IsWired:  r.Intn(3) == 0,
+			LastSeen: TimeStamp(r.Int63n(1 << 31)),
+		}
+	}
+
+	return clients
+}
+
+// BenchmarkClientSorter sorts 10k clients by three keys, with the
+// precomputed keys of ClientSorter and with the less-function chain it
+// replaced.
+func BenchmarkClientSorter(b *testing.B) {
+	clients := benchmarkClients(10000)
+	work := make([]Client, len(clients))
+
+	b.Run("keys", func(b *testing.B) {
+		sorter := ClientOrderedBy(ClientWired, ClientName, ClientIP)
+
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			copy(work, clients)
+			sorter.Sort(work)
+		}
+	})
+
+	b.Run("less", func(b *testing.B) {
+		sorter := &lessSorter{less: []func(lhs, rhs *Client) bool{
+			func(lhs, rhs *Client) bool { return lhs.IsWired && !rhs.IsWired },
+			func(lhs, rhs *Client) bool { return lhs.DisplayName() < rhs.DisplayName() },
+			func(lhs, rhs *Client) bool { return lhs.IP.Less(rhs.IP) },
+		}}
+
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			copy(work, clients)
+			sorter.clients = work
+			sort.Sort(sorter)
+		}
+	})
+}
+
+// BenchmarkClientDisplayName sorts 10k clients by name with the less
+// function chain, which calls DisplayName twice per comparison, on clients
+// built in code (computed on each call) and on unmarshalled clients
+// (cached).
+func BenchmarkClientDisplayName(b *testing.B) {
+	built := benchmarkClients(10000)
+	for ix := range built {
+		// Fall through to the MAC, the last of the candidates.
+		built[ix].Hostname = ""
+	}
+
+	data, err := json.Marshal(built)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var decoded []Client
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		b.Fatal(err)
+	}
+
+	byName := func(lhs, rhs *Client) bool { return lhs.DisplayName() < rhs.DisplayName() }
+
+	for _, bc := range []struct {
+		name    string
+		clients []Client
+	}{
+		{"computed", built},
+		{"cached", decoded},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			work := make([]Client, len(bc.clients))
+			sorter := &lessSorter{less: []func(lhs, rhs *Client) bool{byName}}
+
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				copy(work, bc.clients)
+				sorter.clients = work
+				sort.Sort(sorter)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package unifi
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultMaxClockSkew is the difference from the controller's clock above
+// which a warning is written.
+var DefaultMaxClockSkew = 30 * time.Second
+
+// WithMaxClockSkew sets the difference between the local clock and the
+// Date header of the controller's responses above which a warning is
+// written, once per session. A negative value disables the check.
+func WithMaxClockSkew(d time.Duration) Option { return func(s *Session) { s.maxClockSkew = d } }
+
+// checkClockSkew warns once if date, the Date header of a response to a
+// request sent at sent, is further from the local clock than allowed.
+func (s *Session) checkClockSkew(date string, sent time.Time) {
+	if s.maxClockSkew < 0 || s.skewWarned || date == "" {
+		return
+	}
+
+	server, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	// The header has a resolution of a second, so compare against the
+	// midpoint of the request and allow for the truncation.
+	skew := sent.Add(time.Since(sent) / 2).Sub(server)
+	if skew.Abs() <= s.maxClockSkew+time.Second {
+		return
+	}
+
+	s.skewWarned = true
+
+	direction := "ahead of"
+	if skew < 0 {
+		direction = "behind"
+	}
+
+	fmt.Fprintf(s.errWriter, "warning: the local clock is %s %s the controller's; "+
+		"sessions and certificate checks may fail until it is synced\n", skew.Abs().Round(time.Second), direction)
+}
+
+// clockHint adds a hint to check the local clock to certificate validity
+// errors, which a wrong clock causes.
+func clockHint(err error) error {
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) && invalid.Reason == x509.Expired {
+		return fmt.Errorf("%w (is the local clock correct? it is %s)", err, time.Now().Format(time.RFC3339))
+	}
+
+	return err
+}
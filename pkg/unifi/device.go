@@ -86,6 +86,7 @@ type Device struct {
 	NextInterval               Duration              `json:"next_interval,omitempty"`
 	NumSTA                     int64                 `json:"num_sta,omitempty"`
 	OutdoorModeOverride        string                `json:"outdoor_mode_override,omitempty"`
+	PortOverrides              []PortOverride        `json:"port_overrides,omitempty"`
 	PortTable                  []Port                `json:"port_table,omitempty"`
 	PreviousNonBusyState       int64                 `json:"prev_non_busy_state,omitempty"`
 	ProvisionedAt              TimeStamp             `json:"provisioned_at,omitempty"`
@@ -122,7 +123,10 @@ type Device struct {
 
 func (d *Device) UniqueID() string { return d.ID }
 
-func (d *Device) String() string {
+func (d *Device) String() string { return d.Display(DisplayOptions{}) }
+
+// Display renders the device on one line.
+func (d *Device) Display(o DisplayOptions) string {
 	traffic := ""
 	if d.BytesReceived+d.BytesSent > 0 {
 		recvd := formatBytesSize(d.BytesReceived)
@@ -135,7 +139,7 @@ func (d *Device) String() string {
 		temp = fmt.Sprintf("%d°C", d.GeneralTemperature)
 	}
 
-	return fmt.Sprintf("%25s   %-15s %-4s %-35s %s", d.Name, d.IP, temp, d.SystemStats, traffic)
+	return fmt.Sprintf("%25s   %-15s %-17s %-4s %-35s %s", d.Name, d.IP, d.ConnectionState(), temp, d.SystemStats.Display(o), traffic)
 }
 
 type ConfigNetwork struct {
@@ -266,14 +270,17 @@ type SystemStats struct {
 	Uptime string `json:"uptime,omitempty"`
 }
 
-func (s SystemStats) String() string {
+func (s SystemStats) String() string { return s.Display(DisplayOptions{}) }
+
+// Display renders the load and, as o says, when the device came up.
+func (s SystemStats) Display(o DisplayOptions) string {
 	if len(s.CPU)+len(s.Mem)+len(s.Uptime) == 0 {
 		return ""
 	}
 
 	uptime := ""
 	if u, err := strconv.ParseInt(s.Uptime, 10, 64); err == nil {
-		uptime = Duration(u).String()
+		uptime = Duration(u).Display(o)
 	}
 	return fmt.Sprintf("%4s%% cpu / %-4s%% mem  %s", s.CPU, s.Mem, uptime)
 }
@@ -0,0 +1,270 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/itchyny/gojq"
+	"gopkg.in/yaml.v3"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi/schema"
+)
+
+const (
+	FormatTable = "table"
+	FormatWide  = "wide"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// Formats lists the supported output formats. FormatWide is a table with
+// extra columns where a command has them, and the normal table otherwise.
+var Formats = []string{FormatTable, FormatWide, FormatJSON, FormatYAML}
+
+// IsTable reports whether format is a human readable table.
+func IsTable(format string) bool { return format == FormatTable || format == FormatWide }
+
+// IsStructured reports whether format is a machine readable format.
+func IsStructured(format string) bool { return format == FormatJSON || format == FormatYAML }
+
+// Option changes how Write, Project, and Query render data.
+type Option func(*rewriter)
+
+// WithRFC3339Times writes unifi.TimeStamp and unifi.TimeStampMilliseconds
+// values as RFC 3339 strings in loc, instead of the numbers the API uses.
+func WithRFC3339Times(loc *time.Location) Option {
+	return func(r *rewriter) { r.times = loc }
+}
+
+// Write serializes data to out in the requested structured format. If
+// fields is not empty, only those (JSON) keys are kept in each object;
+// otherwise synthetic fields are left out.
+func Write(out io.Writer, format string, fields []string, data any, options ...Option) error {
+	projected, err := Project(data, fields, options...)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(projected)
+	case FormatYAML:
+		enc := yaml.NewEncoder(out)
+		enc.SetIndent(2)
+
+		if err = enc.Encode(projected); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	default:
+		return fmt.Errorf("unsupported output format %q (one of %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Project converts data into its generic JSON representation, keeping only
+// the named keys of each object. Nested slices of objects are projected
+// element by element. Without fields, the fields of data tagged
+// `schema:"synthetic"` are dropped instead, so they are only written when
+// asked for by name.
+func Project(data any, fields []string, options ...Option) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling output: %w", err)
+	}
+
+	var generic any
+	if err = json.Unmarshal(raw, &generic); err != nil {
+		return nil, fmt.Errorf("unmarshalling output: %w", err)
+	}
+
+	r := rewriter{omitSynthetic: len(fields) == 0}
+	for _, option := range options {
+		option(&r)
+	}
+
+	generic = r.value(reflect.ValueOf(data), generic)
+
+	if len(fields) == 0 {
+		return generic, nil
+	}
+
+	keep := map[string]bool{}
+	for _, field := range fields {
+		keep[strings.TrimSpace(field)] = true
+	}
+
+	return project(generic, keep), nil
+}
+
+func project(v any, keep map[string]bool) any {
+	switch val := v.(type) {
+	case []any:
+		for ix := range val {
+			val[ix] = project(val[ix], keep)
+		}
+
+		return val
+	case map[string]any:
+		for k := range val {
+			if !keep[k] {
+				delete(val, k)
+			}
+		}
+
+		return val
+	default:
+		return v
+	}
+}
+
+// rewriter changes g, the generic JSON representation of v, where that
+// depends on the Go types in v: it drops synthetic fields and formats
+// timestamps.
+type rewriter struct {
+	omitSynthetic bool
+	times         *time.Location // nil leaves timestamps as numbers
+}
+
+func (r rewriter) value(v reflect.Value, g any) any {
+	if !r.omitSynthetic && r.times == nil {
+		return g
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return g
+		}
+
+		v = v.Elem()
+	}
+
+	if r.times != nil && v.CanInterface() {
+		switch t := v.Interface().(type) {
+		case unifi.TimeStamp:
+			return t.Time().In(r.times).Format(time.RFC3339)
+		case unifi.TimeStampMilliseconds:
+			return t.Time().In(r.times).Format(time.RFC3339)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if obj, ok := g.(map[string]any); ok {
+			r.fields(v, obj)
+		}
+	case reflect.Slice, reflect.Array:
+		if list, ok := g.([]any); ok && len(list) == v.Len() {
+			for ix := range list {
+				list[ix] = r.value(v.Index(ix), list[ix])
+			}
+		}
+	case reflect.Map:
+		if obj, ok := g.(map[string]any); ok && v.Type().Key().Kind() == reflect.String {
+			for iter := v.MapRange(); iter.Next(); {
+				if val, ok := obj[iter.Key().String()]; ok {
+					obj[iter.Key().String()] = r.value(iter.Value(), val)
+				}
+			}
+		}
+	default:
+	}
+
+	return g
+}
+
+func (r rewriter) fields(v reflect.Value, obj map[string]any) {
+	t := v.Type()
+
+	for ix := 0; ix < t.NumField(); ix++ {
+		f := t.Field(ix)
+
+		name, ok := schema.JSONName(f)
+		if !ok {
+			continue
+		}
+
+		if f.Anonymous && len(name) == 0 {
+			// Fields of embedded structs are promoted into obj.
+			r.value(v.Field(ix), obj)
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = f.Name
+		}
+
+		val, ok := obj[name]
+		if !ok {
+			continue
+		}
+
+		if r.omitSynthetic && schema.IsSynthetic(f) {
+			delete(obj, name)
+
+			continue
+		}
+
+		obj[name] = r.value(v.Field(ix), val)
+	}
+}
+
+// Query runs the jq expression over the JSON representation of data (after
+// projecting fields) and writes each result in turn. String results are
+// written raw, one per line, as with jq -r; other results are written in
+// format, or as JSON when format is not structured.
+func Query(out io.Writer, format string, fields []string, expr string, data any, options ...Option) error {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return fmt.Errorf("parsing jq expression: %w", err)
+	}
+
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return fmt.Errorf("compiling jq expression: %w", err)
+	}
+
+	projected, err := Project(data, fields, options...)
+	if err != nil {
+		return err
+	}
+
+	if !IsStructured(format) {
+		format = FormatJSON
+	}
+
+	iter := code.Run(projected)
+
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+
+		switch val := v.(type) {
+		case error:
+			return fmt.Errorf("running jq expression: %w", val)
+		case string:
+			if _, err = fmt.Fprintln(out, val); err != nil {
+				return err
+			}
+		default:
+			if err = Write(out, format, nil, val); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package display
+
+import (
+	"testing"
+	"time"
+
+	"github.com/johnweldon/unifi-scheduler/pkg/unifi"
+)
+
+func TestProjectSynthetic(t *testing.T) {
+	clients := []unifi.Client{{Name: "laptop", MAC: "00:11:22:33:44:55", UpstreamName: "office-ap"}}
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   bool // whether upstream_name is written
+	}{
+		{"all fields", nil, false},
+		{"named", []string{"name", "upstream_name"}, true},
+		{"not named", []string{"name"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			projected, err := Project(clients, tc.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := projected.([]any)[0].(map[string]any)
+
+			if obj["name"] != "laptop" {
+				t.Errorf("got name %v, want laptop", obj["name"])
+			}
+
+			if _, ok := obj["upstream_name"]; ok != tc.want {
+				t.Errorf("got upstream_name %t, want %t", ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestProjectRFC3339Times(t *testing.T) {
+	events := []unifi.Event{{Key: unifi.EventTypeLANUserConnected, TimeStamp: 1_700_000_000_000}}
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    any
+	}{
+		{"numbers", nil, float64(1_700_000_000_000)},
+		{"rfc3339", []Option{WithRFC3339Times(time.UTC)}, "2023-11-14T22:13:20Z"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			projected, err := Project(events, nil, tc.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := projected.([]any)[0].(map[string]any)
+
+			if obj["time"] != tc.want {
+				t.Errorf("got time %v, want %v", obj["time"], tc.want)
+			}
+		})
+	}
+}
+
+func TestProjectRFC3339ClientTimes(t *testing.T) {
+	clients := []unifi.Client{{Name: "laptop", FirstSeen: 1_600_000_000, LastSeen: 1_700_000_000}}
+
+	projected, err := Project(clients, nil, WithRFC3339Times(time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := projected.([]any)[0].(map[string]any)
+
+	for key, want := range map[string]string{"first_seen": "2020-09-13T12:26:40Z", "last_seen": "2023-11-14T22:13:20Z"} {
+		if obj[key] != want {
+			t.Errorf("got %s %v, want %s", key, obj[key], want)
+		}
+	}
+}
@@ -15,7 +15,18 @@ type Renderer interface {
 	Render() string
 }
 
-func ClientsTable(out io.Writer, clients []unifi.Client) Renderer {
+func ClientsTable(out io.Writer, o unifi.DisplayOptions, clients []unifi.Client) Renderer {
+	return clientsTable(out, o, clients, false)
+}
+
+// WideClientsTable is ClientsTable with the SSID, channel, signal,
+// satisfaction, first seen, and VLAN columns added. The access point a
+// wireless client is associated with is already shown under Link.
+func WideClientsTable(out io.Writer, o unifi.DisplayOptions, clients []unifi.Client) Renderer {
+	return clientsTable(out, o, clients, true)
+}
+
+func clientsTable(out io.Writer, o unifi.DisplayOptions, clients []unifi.Client, wide bool) Renderer {
 	configs := []table.ColumnConfig{
 		{Name: "Name", Align: text.AlignRight, AlignHeader: text.AlignRight, AlignFooter: text.AlignRight, WidthMax: 25},
 		{Name: "B"},
@@ -30,6 +41,17 @@ func ClientsTable(out io.Writer, clients []unifi.Client) Renderer {
 		{Name: "Link"},
 	}
 
+	if wide {
+		configs = append(configs,
+			table.ColumnConfig{Name: "SSID", WidthMax: 25},
+			table.ColumnConfig{Name: "Channel", Align: text.AlignRight, AlignHeader: text.AlignRight},
+			table.ColumnConfig{Name: "Signal", Align: text.AlignRight, AlignHeader: text.AlignRight},
+			table.ColumnConfig{Name: "Satisfaction", Align: text.AlignRight, AlignHeader: text.AlignRight},
+			table.ColumnConfig{Name: "First Seen"},
+			table.ColumnConfig{Name: "VLAN", Align: text.AlignRight, AlignHeader: text.AlignRight},
+		)
+	}
+
 	headerRow := table.Row{}
 	for _, c := range configs {
 		headerRow = append(headerRow, c.Name)
@@ -42,25 +64,57 @@ func ClientsTable(out io.Writer, clients []unifi.Client) Renderer {
 
 	t.AppendHeader(headerRow)
 	for _, client := range clients {
-		t.AppendRow([]interface{}{
+		row := table.Row{
 			client.DisplayName(),
 			string(client.IsBlockedGlyph()),
 			string(client.IsGuestGlyph()),
 			string(client.IsWiredGlyph()),
-			client.DisplayIP(),
-			client.DisplayLastAssociated(),
+			client.DisplayIP(o),
+			client.DisplayLastAssociated(o),
 			client.DisplayReceivedBytes(),
 			client.DisplaySentBytes(),
 			client.DisplayReceiveRate(),
 			client.DisplaySendRate(),
 			client.DisplaySwitchName(),
-		})
+		}
+
+		if wide {
+			row = append(row,
+				firstNonEmpty(client.ESSID, "-"),
+				optional(client.Channel, "%d"),
+				optional(client.Signal, "%d dBm"),
+				optional(client.Satisfaction, "%d%%"),
+				client.DisplayFirstSeen(o),
+				optional(client.VLAN, "%d"),
+			)
+		}
+
+		t.AppendRow(row)
 	}
 	t.AppendFooter(table.Row{fmt.Sprintf("Total %d", t.Length())})
 	return t
 }
 
-func EventsTable(out io.Writer, displayName func(unifi.MAC) (string, bool), events []unifi.Event) Renderer {
+func firstNonEmpty(s ...string) string {
+	for _, candidate := range s {
+		if len(candidate) > 0 {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// optional formats n, or returns "-" if it is zero, as unset values are.
+func optional(n int64, format string) string {
+	if n == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf(format, n)
+}
+
+func EventsTable(out io.Writer, o unifi.DisplayOptions, displayName func(unifi.MAC) (string, bool), events []unifi.Event) Renderer {
 	configs := []table.ColumnConfig{
 		{Name: "Name", Align: text.AlignRight, AlignHeader: text.AlignRight, AlignFooter: text.AlignRight, WidthMax: 25},
 		{Name: "Event", WidthMax: 15},
@@ -220,7 +274,7 @@ func EventsTable(out io.Writer, displayName func(unifi.MAC) (string, bool), even
 		}
 
 		t.AppendRow([]interface{}{
-			name, evt, from, to, event.TimeStamp.ShortTime(), event.TimeStamp.String(),
+			name, evt, from, to, event.TimeStamp.ShortTime(o), event.TimeStamp.Display(o),
 		})
 	}
 
@@ -238,3 +292,23 @@ var StyleDefault = table.Style{
 	Options: table.OptionsNoBordersAndSeparators,
 	Title:   table.TitleOptionsBright,
 }
+
+// ClientsByGroup renders a titled ClientsTable, or WideClientsTable if wide
+// is set, for each non-empty group, in Groups.Names order. Each table's
+// footer is the group subtotal.
+func ClientsByGroup(out io.Writer, o unifi.DisplayOptions, groups unifi.Groups, clients []unifi.Client, wide bool) {
+	byGroup := map[string][]unifi.Client{}
+	for ix := range clients {
+		name := groups.ClientGroup(&clients[ix])
+		byGroup[name] = append(byGroup[name], clients[ix])
+	}
+
+	for _, name := range groups.Names() {
+		if len(byGroup[name]) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(out, "\n== %s (%d) ==\n", name, len(byGroup[name]))
+		clientsTable(out, o, byGroup[name], wide).Render()
+	}
+}
@@ -3,6 +3,7 @@ package unifi
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"time"
 )
 
@@ -130,7 +131,115 @@ type Event struct {
 
 func (e Event) UniqueID() string { return e.ID }
 
-func (e Event) String() string {
+// Details returns the identifying fields of the event that are set, keyed
+// by their API names, e.g. for structured logging.
+func (e Event) Details() map[string]string {
+	details := map[string]string{}
+
+	for k, v := range map[string]string{
+		"_id":       e.ID,
+		"key":       string(e.Key),
+		"subsystem": e.Subsystem,
+		"site_id":   e.SiteID,
+		"mac":       string(e.MAC),
+		"user":      string(e.User),
+		"guest":     string(e.Guest),
+		"client":    string(e.Client),
+		"ap":        string(e.AccessPoint),
+		"ap_from":   string(e.AccessPointFrom),
+		"ap_to":     string(e.AccessPointTo),
+		"ap_name":   e.AccessPointName,
+		"sw":        string(e.Switch),
+		"sw_name":   e.SwitchName,
+		"gw":        string(e.Gateway),
+		"bb":        string(e.Bridge),
+		"dm":        string(e.DM),
+		"ip":        string(e.IP),
+		"hostname":  e.Hostname,
+		"ssid":      firstNonEmpty(e.SSID, e.ESSID),
+		"network":   e.Network,
+		"admin":     e.Admin,
+	} {
+		if v != "" {
+			details[k] = v
+		}
+	}
+
+	if e.Port > 0 {
+		details["port"] = strconv.FormatInt(e.Port, 10)
+	}
+
+	if e.VLAN > 0 {
+		details["vlan"] = strconv.FormatInt(e.VLAN, 10)
+	}
+
+	return details
+}
+
+// EventSeverity classifies how noteworthy an Event is.
+type EventSeverity int
+
+const (
+	SeverityInfo EventSeverity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s EventSeverity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// Severity classifies the event based on the event type.
+func (e Event) Severity() EventSeverity {
+	switch e.Key {
+	case
+		EventTypeAccessPointDetectRogueAP,
+		EventTypeAccessPointLostContact,
+		EventTypeBridgeLostContact,
+		EventTypeGatewayWANTransition,
+		EventTypeSwitchDetectRogueDHCP,
+		EventTypeSwitchLostContact:
+
+		return SeverityCritical
+
+	case
+		EventTypeADScheduleUpgradeFailedNotFound,
+		EventTypeAccessPointIsolated,
+		EventTypeAccessPointPossibleInterference,
+		EventTypeAccessPointRestartedUnknown,
+		EventTypeAccessPointUpgradeFailed,
+		EventTypeBridgeRestartedUnknown,
+		EventTypeBridgeUpgradeFailed,
+		EventTypeSwitchFirmwareCheckFailed,
+		EventTypeSwitchFirmwareDownloadFailed,
+		EventTypeSwitchPOEDisconnect,
+		EventTypeSwitchRestartedUnknown,
+		EventTypeSwitchSTPPortBlocking,
+		EventTypeSwitchUpgradeFailed:
+
+		return SeverityWarning
+	}
+
+	if e.IsNegative {
+		return SeverityWarning
+	}
+
+	return SeverityInfo
+}
+
+func (e Event) String() string { return e.Display(DisplayOptions{}) }
+
+// Display renders the event on one line, its time in o's zone.
+func (e Event) Display(o DisplayOptions) string {
 	const maxMsgLen = 100
 
 	msg := e.Message
@@ -141,7 +250,7 @@ func (e Event) String() string {
 	return fmt.Sprintf(
 		"%25s %-30s %s",
 		e.Key,
-		e.DateTime,
+		o.In(e.DateTime),
 		msg,
 	)
 }
@@ -0,0 +1,77 @@
+package unifi
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Ungrouped is the group of clients and devices that match no group.
+const Ungrouped = "ungrouped"
+
+// Groups maps group names to case-insensitive glob patterns matched
+// against MACs and names, e.g. {"cameras": ["cam-*", "aa:bb:cc:*"]}.
+type Groups map[string][]string
+
+// Names returns the group names in sorted order, followed by Ungrouped.
+func (g Groups) Names() []string {
+	names := make([]string, 0, len(g)+1)
+	for name := range g {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return append(names, Ungrouped)
+}
+
+// ClientGroup returns the first group, in Names order, matching the client.
+func (g Groups) ClientGroup(c *Client) string {
+	return g.group(c.MAC.String(), c.Name, c.Hostname, c.DisplayName())
+}
+
+// DeviceGroup returns the first group, in Names order, matching the device.
+func (g Groups) DeviceGroup(d *Device) string {
+	return g.group(d.MAC.String(), d.Name)
+}
+
+func (g Groups) group(candidates ...string) string {
+	for _, name := range g.Names() {
+		if MatchAny(g[name], candidates...) {
+			return name
+		}
+	}
+
+	return Ungrouped
+}
+
+// InGroup matches clients in any of the named groups.
+func InGroup(g Groups, names ...string) ClientFilter {
+	return func(c Client) bool { return slices.Contains(names, g.ClientGroup(&c)) }
+}
+
+// InDeviceGroup matches devices in any of the named groups.
+func InDeviceGroup(g Groups, names ...string) DeviceFilter {
+	return func(d Device) bool { return slices.Contains(names, g.DeviceGroup(&d)) }
+}
+
+// MatchAny reports whether any non-empty candidate matches any of the
+// case-insensitive glob patterns.
+func MatchAny(patterns []string, candidates ...string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+
+		for _, candidate := range candidates {
+			if len(candidate) == 0 {
+				continue
+			}
+
+			if ok, _ := path.Match(pattern, strings.ToLower(candidate)); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package unifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// GuestAuthorization describes a guest network authorization record.
+//
+// This is named to avoid colliding with the Guest ClientFilter.
+type GuestAuthorization struct {
+	ID string `json:"_id,omitempty"`
+
+	AccessPointMAC  MAC    `json:"ap_mac,omitempty"`
+	AuthorizedBy    string `json:"authorized_by,omitempty"`
+	AuthorizedUntil int64  `json:"authorized_until,omitempty"`
+	Bytes           int64  `json:"bytes,omitempty"`
+	BytesReceived   int64  `json:"rx_bytes,omitempty"`
+	BytesSent       int64  `json:"tx_bytes,omitempty"`
+	Duration        int64  `json:"duration,omitempty"`
+	End             int64  `json:"end,omitempty"`
+	Hostname        string `json:"hostname,omitempty"`
+	IP              IP     `json:"ip,omitempty"`
+	IsExpired       bool   `json:"expired,omitempty"`
+	MAC             MAC    `json:"mac,omitempty"`
+	Name            string `json:"name,omitempty"`
+	QOSOverwrite    bool   `json:"qos_overwrite,omitempty"`
+	QOSRateMaxDown  int64  `json:"qos_rate_max_down,omitempty"`
+	QOSRateMaxUp    int64  `json:"qos_rate_max_up,omitempty"`
+	QOSUsageQuota   int64  `json:"qos_usage_quota,omitempty"`
+	SiteID          string `json:"site_id,omitempty"`
+	Start           int64  `json:"start,omitempty"`
+	UserAgent       string `json:"user_agent,omitempty"`
+	VoucherCode     string `json:"voucher_code,omitempty"`
+	VoucherID       string `json:"voucher_id,omitempty"`
+}
+
+// Started returns the time the authorization started.
+func (g *GuestAuthorization) Started() time.Time { return time.Unix(g.Start, 0) }
+
+// Ends returns the time the authorization expires.
+func (g *GuestAuthorization) Ends() time.Time { return time.Unix(g.End, 0) }
+
+// DisplayName returns the most useful identifier for the guest.
+func (g *GuestAuthorization) DisplayName() string {
+	return firstNonEmpty(g.Name, g.Hostname, string(g.MAC), "-")
+}
+
+func (g *GuestAuthorization) String() string { return g.Display(DisplayOptions{}) }
+
+// Display renders the authorization on one line, its times in o's zone.
+func (g *GuestAuthorization) Display(o DisplayOptions) string {
+	expired := " "
+	if g.IsExpired {
+		expired = "✗"
+	}
+
+	return fmt.Sprintf("%25s %s %-15s %-25s %-25s %11s↓ %11s↑ %s",
+		g.DisplayName(),
+		expired,
+		g.IP,
+		o.In(g.Started()).Format(time.RFC3339),
+		o.In(g.Ends()).Format(time.RFC3339),
+		formatBytesSize(g.BytesReceived),
+		formatBytesSize(g.BytesSent),
+		g.AuthorizedBy,
+	)
+}
+
+// ListGuests describes the guest authorizations.
+func (s *Session) ListGuests() (string, error) { return s.action(http.MethodGet, "/stat/guest", nil) }
+
+// GetGuests returns the guest authorization records, ordered by start time.
+func (s *Session) GetGuests() ([]GuestAuthorization, error) {
+	var (
+		data string
+		resp GuestResponse
+		err  error
+	)
+
+	if data, err = s.ListGuests(); err != nil {
+		return nil, fmt.Errorf("fetching guests: %w", err)
+	}
+
+	if err = json.Unmarshal([]byte(data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshalling guests: %w", err)
+	}
+
+	guests := resp.Data
+
+	sort.SliceStable(guests, func(i, j int) bool { return guests[i].Start < guests[j].Start })
+
+	return guests, nil
+}
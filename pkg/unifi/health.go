@@ -0,0 +1,88 @@
+package unifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SubsystemHealth describes the health of a single controller subsystem
+// (www, wan, lan, wlan, vpn).
+type SubsystemHealth struct {
+	Subsystem string `json:"subsystem,omitempty"`
+	Status    string `json:"status,omitempty"`
+
+	BytesReceivedRate  float64  `json:"rx_bytes-r,omitempty"`
+	BytesSentRate      float64  `json:"tx_bytes-r,omitempty"`
+	Drops              int64    `json:"drops,omitempty"`
+	GatewayMAC         MAC      `json:"gw_mac,omitempty"`
+	ISPName            string   `json:"isp_name,omitempty"`
+	Latency            int64    `json:"latency,omitempty"`
+	NumAP              int64    `json:"num_ap,omitempty"`
+	NumAdopted         int64    `json:"num_adopted,omitempty"`
+	NumDisabled        int64    `json:"num_disabled,omitempty"`
+	NumDisconnected    int64    `json:"num_disconnected,omitempty"`
+	NumGateway         int64    `json:"num_gw,omitempty"`
+	NumGuest           int64    `json:"num_guest,omitempty"`
+	NumPending         int64    `json:"num_pending,omitempty"`
+	NumSwitch          int64    `json:"num_sw,omitempty"`
+	NumUser            int64    `json:"num_user,omitempty"`
+	RemoteUserEnabled  bool     `json:"remote_user_enabled,omitempty"`
+	RemoteUserNumUsers int64    `json:"remote_user_num_active,omitempty"`
+	SiteToSiteEnabled  bool     `json:"site_to_site_enabled,omitempty"`
+	SpeedTestDownload  float64  `json:"xput_down,omitempty"`
+	SpeedTestLastRun   int64    `json:"speedtest_lastrun,omitempty"`
+	SpeedTestPing      int64    `json:"speedtest_ping,omitempty"`
+	SpeedTestStatus    string   `json:"speedtest_status,omitempty"`
+	SpeedTestUpload    float64  `json:"xput_up,omitempty"`
+	Uptime             Duration `json:"uptime,omitempty"`
+	WANIP              IP       `json:"wan_ip,omitempty"`
+	WANNameservers     []IP     `json:"nameservers,omitempty"`
+}
+
+// IsOK reports whether the subsystem status is "ok".
+func (h SubsystemHealth) IsOK() bool { return h.Status == "ok" }
+
+func (h SubsystemHealth) String() string { return h.Display(DisplayOptions{}) }
+
+// Display renders the subsystem's status and details on one line.
+func (h SubsystemHealth) Display(o DisplayOptions) string {
+	detail := ""
+
+	switch h.Subsystem {
+	case "www":
+		detail = fmt.Sprintf("latency %dms  up %s", h.Latency, h.Uptime.Display(o))
+	case "wan":
+		detail = fmt.Sprintf("%s %s", h.WANIP, h.ISPName)
+	case "lan":
+		detail = fmt.Sprintf("%d users  %d guests  %d switches", h.NumUser, h.NumGuest, h.NumSwitch)
+	case "wlan":
+		detail = fmt.Sprintf("%d users  %d guests  %d aps", h.NumUser, h.NumGuest, h.NumAP)
+	}
+
+	return fmt.Sprintf("%-6s %-12s %s", h.Subsystem, h.Status, detail)
+}
+
+// ListHealth describes the health of the controller subsystems.
+func (s *Session) ListHealth() (string, error) {
+	return s.action(http.MethodGet, "/stat/health", nil)
+}
+
+// GetHealth returns the health of each controller subsystem.
+func (s *Session) GetHealth() ([]SubsystemHealth, error) {
+	var (
+		data string
+		resp HealthResponse
+		err  error
+	)
+
+	if data, err = s.ListHealth(); err != nil {
+		return nil, fmt.Errorf("fetching health: %w", err)
+	}
+
+	if err = json.Unmarshal([]byte(data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshalling health: %w", err)
+	}
+
+	return resp.Data, nil
+}
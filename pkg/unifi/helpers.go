@@ -2,8 +2,11 @@ package unifi
 
 import (
 	"encoding/json"
+	"fmt"
 	"net"
+	"net/netip"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -19,12 +22,90 @@ type (
 	Number                int64
 )
 
-func (d Duration) String() string {
-	return humanize.Time(time.Now().Add(-time.Second * time.Duration(d)))
+func (d Duration) String() string { return d.Display(DisplayOptions{}) }
+
+// Display renders when the duration started, counting back from now.
+func (d Duration) Display(o DisplayOptions) string {
+	return o.Time(time.Now().Add(-time.Second * time.Duration(d)))
+}
+
+func (d DurationMilliseconds) String() string { return d.Display(DisplayOptions{}) }
+
+// Display renders when the duration started, counting back from now.
+func (d DurationMilliseconds) Display(o DisplayOptions) string {
+	return o.Time(time.Now().Add(-time.Millisecond * time.Duration(d)))
+}
+
+func (t TimeStamp) String() string { return t.Display(DisplayOptions{}) }
+
+// Display renders the timestamp as o says.
+func (t TimeStamp) Display(o DisplayOptions) string { return o.Time(t.Time()) }
+
+func (t TimeStamp) ShortTime(o DisplayOptions) string {
+	return o.In(t.Time()).Format("03:04:05PM")
+}
+
+// Time converts the timestamp. The API uses TimeStamp fields for both
+// milliseconds (events) and seconds (devices) since the epoch; values
+// too small to be a time after 1973 in milliseconds are taken as seconds.
+func (t TimeStamp) Time() time.Time {
+	if t < secondsCutoff {
+		return time.Unix(int64(t), 0)
+	}
+
+	return time.UnixMilli(int64(t))
+}
+
+// secondsCutoff is 1973-03-03 in milliseconds, or 5138 in seconds.
+const secondsCutoff = 100_000_000_000
+
+func (t TimeStampMilliseconds) String() string { return t.Display(DisplayOptions{}) }
+
+// Display renders the timestamp as o says.
+func (t TimeStampMilliseconds) Display(o DisplayOptions) string { return o.Time(t.Time()) }
+
+func (t TimeStampMilliseconds) Time() time.Time { return time.UnixMilli(int64(t)) }
+
+// UnmarshalJSON accepts the API's number, or an RFC 3339 string as written
+// by display.WithRFC3339Times.
+func (t *TimeStamp) UnmarshalJSON(b []byte) error {
+	ms, err := unmarshalTime(b)
+	*t = TimeStamp(ms)
+
+	return err
+}
+
+// UnmarshalJSON accepts the API's number, or an RFC 3339 string as written
+// by display.WithRFC3339Times.
+func (t *TimeStampMilliseconds) UnmarshalJSON(b []byte) error {
+	ms, err := unmarshalTime(b)
+	*t = TimeStampMilliseconds(ms)
+
+	return err
 }
 
-func (t TimeStamp) String() string    { return humanize.Time(time.UnixMilli(int64(t))) }
-func (t TimeStamp) ShortTime() string { return time.UnixMilli(int64(t)).Format("03:04:05PM") }
+// unmarshalTime decodes a number as is, or an RFC 3339 string as
+// milliseconds since the epoch.
+func unmarshalTime(b []byte) (int64, error) {
+	if len(b) == 0 || b[0] != '"' {
+		var n int64
+		err := json.Unmarshal(b, &n)
+
+		return n, err
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return 0, err
+	}
+
+	tm, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, err
+	}
+
+	return tm.UnixMilli(), nil
+}
 
 func (m MAC) String() string {
 	if len(m) == 0 {
@@ -67,6 +148,60 @@ func (lhs IP) Less(rhs IP) bool {
 	return false
 }
 
+// sortKey orders IPs as Less does: empty first, then unparseable, then by
+// address.
+func (ip IP) sortKey() string {
+	if len(ip) == 0 {
+		return ""
+	}
+
+	parsed := net.ParseIP(string(ip))
+	if parsed == nil {
+		return "\x00"
+	}
+
+	return "\x01" + string(parsed.To16())
+}
+
+// Addr parses the IP, reporting false if it is empty or invalid.
+func (ip IP) Addr() (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(string(ip))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.Unmap(), true
+}
+
+// Subnet is an IP network prefix, e.g. 192.168.10.0/24.
+type Subnet struct {
+	prefix netip.Prefix
+}
+
+// ParseSubnet parses a CIDR string into a Subnet. A bare address is
+// treated as a single host prefix.
+func ParseSubnet(cidr string) (Subnet, error) {
+	if prefix, err := netip.ParsePrefix(cidr); err == nil {
+		return Subnet{prefix: prefix.Masked()}, nil
+	}
+
+	addr, err := netip.ParseAddr(cidr)
+	if err != nil {
+		return Subnet{}, fmt.Errorf("invalid subnet %q", cidr)
+	}
+
+	return Subnet{prefix: netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())}, nil
+}
+
+func (s Subnet) String() string { return s.prefix.String() }
+
+// Contains reports whether ip is within the subnet.
+func (s Subnet) Contains(ip IP) bool {
+	addr, ok := ip.Addr()
+
+	return ok && s.prefix.Contains(addr)
+}
+
 func (n *Number) UnmarshalJSON(b []byte) error {
 	if len(b) == 0 {
 		return nil
@@ -101,15 +236,106 @@ func (n *Number) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// Units controls how sizes and rates are displayed.
+type Units struct {
+	// IEC uses binary prefixes for sizes (1 KiB = 1024 B) instead of
+	// SI prefixes (1 kB = 1000 B).
+	IEC bool
+	// RateBytes displays rates in bytes per second (MB/s) instead of
+	// bits per second (Mbps).
+	RateBytes bool
+}
+
+// DisplayUnits is used by the Display* helpers.
+var DisplayUnits Units
+
+// Client IP preferences for DisplayOptions.IP.
+const (
+	PreferLeasedIP = "leased"
+	PreferFixedIP  = "fixed"
+)
+
+// DisplayOptions control how the Display methods render values for
+// people. The zero value renders times relative to now, e.g. "2 hours ago",
+// or in the local zone, and prefers a client's leased IP; the String methods
+// use it.
+type DisplayOptions struct {
+	// IP is which address Client.DisplayIP shows when a client has both
+	// a current (leased) IP and a different fixed IP (DHCP reservation):
+	// PreferLeasedIP, the default, or PreferFixedIP. The other is shown
+	// when the preferred one is not set.
+	IP string
+	// TZ is the time zone absolute times are rendered in; nil is the
+	// local zone.
+	TZ *time.Location
+	// TimeLayout, when set, renders times that are otherwise shown
+	// relative to now in this layout, in TZ.
+	TimeLayout string
+}
+
+// Location returns o.TZ, or time.Local if it is not set.
+func (o DisplayOptions) Location() *time.Location {
+	if o.TZ == nil {
+		return time.Local
+	}
+
+	return o.TZ
+}
+
+// In converts t to o.Location().
+func (o DisplayOptions) In(t time.Time) time.Time { return t.In(o.Location()) }
+
+// Time renders t relative to now, or in o.TimeLayout if set.
+func (o DisplayOptions) Time(t time.Time) string {
+	if o.TimeLayout != "" {
+		return o.In(t).Format(o.TimeLayout)
+	}
+
+	return humanize.Time(t)
+}
+
+// formatDuration renders d compactly, to the second under an hour, the
+// minute under a day, and the hour beyond, e.g. "45s", "2h5m", "3d4h".
+func formatDuration(d time.Duration) string {
+	const day = 24 * time.Hour
+
+	switch {
+	case d >= day:
+		return fmt.Sprintf("%dd%dh", d/day, (d%day)/time.Hour)
+	case d >= time.Hour:
+		return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
 // nolint: gomnd
 func formatBytesSize(size int64) string {
 	if size <= 0 {
 		return ""
 	}
 
+	if DisplayUnits.IEC {
+		return humanize.IBytes(uint64(size))
+	}
+
 	return humanize.Bytes(uint64(size))
 }
 
+// formatRate formats a rate given in bits per second.
+// nolint: gomnd
+func formatRate(bitsPerSecond int64) string {
+	if bitsPerSecond <= 0 {
+		return ""
+	}
+
+	if DisplayUnits.RateBytes {
+		return formatBytesSize(bitsPerSecond/8) + "/s"
+	}
+
+	return humanize.SIWithDigits(float64(bitsPerSecond), 1, "bps")
+}
+
 func firstNonEmpty(s ...string) string {
 	for _, candidate := range s {
 		if len(candidate) > 0 {
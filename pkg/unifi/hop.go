@@ -0,0 +1,101 @@
+package unifi
+
+import "fmt"
+
+// Hop is one step on the path from a client up to the gateway. For a
+// device, Port is the port the previous hop is connected to and the port
+// fields describe that port; it is zero for wireless links.
+type Hop struct {
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	MAC          MAC    `json:"mac"`
+	Type         string `json:"type,omitempty"`
+	Model        string `json:"model,omitempty"`
+	State        string `json:"state,omitempty"`
+	Satisfaction int64  `json:"satisfaction,omitempty"`
+	ESSID        string `json:"essid,omitempty"`
+	Signal       int64  `json:"signal,omitempty"`
+	Port         int64  `json:"port,omitempty"`
+	PortName     string `json:"port_name,omitempty"`
+	Link         string `json:"link,omitempty"`
+	PortErrors   int64  `json:"port_errors,omitempty"`
+	PortDropped  int64  `json:"port_dropped,omitempty"`
+}
+
+// TraceClient returns the path from the connected client with the given
+// MAC up through its access point and switches to the gateway, client
+// first.
+func (s *Session) TraceClient(mac MAC) ([]Hop, error) {
+	devices, err := s.GetDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.GetClients()
+	if err != nil {
+		return nil, err
+	}
+
+	return TraceClient(mac, devices, clients)
+}
+
+// TraceClient returns the path from the client with the given MAC to the
+// gateway, using the topology built from devices and clients.
+func TraceClient(mac MAC, devices []Device, clients []Client) ([]Hop, error) {
+	var client *Client
+
+	for ix := range clients {
+		if normalMAC(clients[ix].MAC) == normalMAC(mac) {
+			client = &clients[ix]
+
+			break
+		}
+	}
+
+	if client == nil {
+		return nil, fmt.Errorf("client %s is not connected", mac)
+	}
+
+	path := BuildTopology("", devices, clients).Path(client.MAC)
+	if len(path) == 0 {
+		return nil, fmt.Errorf("client %s not found in the topology", mac)
+	}
+
+	byMAC := map[MAC]*Device{}
+	for ix := range devices {
+		byMAC[normalMAC(devices[ix].MAC)] = &devices[ix]
+	}
+
+	hops := []Hop{{
+		Kind:         TopologyClient,
+		Name:         client.DisplayName(),
+		MAC:          client.MAC,
+		Type:         path[len(path)-1].Type,
+		ESSID:        client.ESSID,
+		Satisfaction: client.Satisfaction,
+		Signal:       client.Signal,
+	}}
+
+	// Walk up from the client's parent, skipping the site at the root.
+	for ix := len(path) - 2; ix > 0; ix-- {
+		node, below := path[ix], path[ix+1]
+
+		hop := Hop{Kind: node.Kind, Name: node.Name, MAC: node.MAC, Type: node.Type, Model: node.Model, State: node.State}
+
+		if d, ok := byMAC[normalMAC(node.MAC)]; ok {
+			hop.Satisfaction = d.Satisfaction
+
+			if port, ok := d.PortByIndex(below.Port); ok && below.Port > 0 {
+				hop.Port = port.PortIndex
+				hop.PortName = port.Name
+				hop.Link = port.LinkSpeed()
+				hop.PortErrors = port.ReceiveErrors + port.SendErrors
+				hop.PortDropped = port.ReceiveDropped + port.SendDropped
+			}
+		}
+
+		hops = append(hops, hop)
+	}
+
+	return hops, nil
+}
@@ -0,0 +1,109 @@
+package unifi
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PortByIndex returns the port with the given port index.
+func (d *Device) PortByIndex(idx int64) (*Port, bool) {
+	for ix := range d.PortTable {
+		if d.PortTable[ix].PortIndex == idx {
+			return &d.PortTable[ix], true
+		}
+	}
+
+	return nil, false
+}
+
+// PoEStatus describes the power over ethernet state of a Port.
+type PoEStatus int
+
+const (
+	PoEUnsupported PoEStatus = iota
+	PoEDisabled
+	PoEPassive
+	PoEEnabled
+	PoEDelivering
+)
+
+func (p PoEStatus) String() string {
+	switch p {
+	case PoEUnsupported:
+		return "n/a"
+	case PoEDisabled:
+		return "off"
+	case PoEPassive:
+		return "passive"
+	case PoEEnabled:
+		return "on"
+	case PoEDelivering:
+		return "delivering"
+	default:
+		return "unknown"
+	}
+}
+
+// PoEStatus derives the PoE state from the raw port fields.
+func (p *Port) PoEStatus() PoEStatus {
+	if !p.IsPortPOE {
+		return PoEUnsupported
+	}
+
+	switch p.POEMode {
+	case "off":
+		return PoEDisabled
+	case "pasv24", "passthrough":
+		return PoEPassive
+	}
+
+	if p.POEGood && p.PoEWatts() > 0 {
+		return PoEDelivering
+	}
+
+	if p.POEEnable || p.POEMode == "auto" {
+		return PoEEnabled
+	}
+
+	return PoEDisabled
+}
+
+// PoEWatts returns the power being delivered, in watts.
+func (p *Port) PoEWatts() float64 {
+	w, err := strconv.ParseFloat(p.POEPower, 64)
+	if err != nil {
+		return 0
+	}
+
+	return w
+}
+
+// LinkSpeed describes the negotiated link speed and duplex, e.g. "1G FD".
+func (p *Port) LinkSpeed() string {
+	if !p.IsUp {
+		return "down"
+	}
+
+	duplex := "HD"
+	if p.IsFullDuplex {
+		duplex = "FD"
+	}
+
+	// nolint: gomnd
+	switch {
+	case p.Speed <= 0:
+		return "up"
+	case p.Speed >= 1000 && p.Speed%1000 == 0:
+		return fmt.Sprintf("%dG %s", p.Speed/1000, duplex)
+	case p.Speed >= 1000:
+		return fmt.Sprintf("%.1fG %s", float64(p.Speed)/1000, duplex)
+	default:
+		return fmt.Sprintf("%dM %s", p.Speed, duplex)
+	}
+}
+
+// DisplayReceiveRate formats the current receive throughput.
+func (p *Port) DisplayReceiveRate() string { return formatRate(int64(p.ReceiveBytesErr * 8)) } // nolint: gomnd
+
+// DisplaySendRate formats the current send throughput.
+func (p *Port) DisplaySendRate() string { return formatRate(int64(p.SendBytesErr * 8)) } // nolint: gomnd
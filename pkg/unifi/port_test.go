@@ -0,0 +1,89 @@
+package unifi
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func loadDevices(t *testing.T, file string) []Device {
+	t.Helper()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp DeviceResponse
+	if err = json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("decoding %s: %v", file, err)
+	}
+
+	return resp.Data
+}
+
+func TestPortHelpers(t *testing.T) {
+	devices := loadDevices(t, "testdata/device_usw.json")
+	if len(devices) != 1 {
+		t.Fatalf("got %d devices, want 1", len(devices))
+	}
+
+	dev := &devices[0]
+
+	tests := []struct {
+		idx   int64
+		poe   PoEStatus
+		watts float64
+		speed string
+	}{
+		{1, PoEDelivering, 4.52, "1G FD"},
+		{2, PoEEnabled, 0, "down"},
+		{3, PoEDisabled, 0, "100M FD"},
+		{4, PoEPassive, 2.10, "1G FD"},
+		{5, PoEUnsupported, 0, "10M HD"},
+		{6, PoEUnsupported, 0, "2.5G FD"},
+		{7, PoEUnsupported, 0, "down"},
+		{8, PoEUnsupported, 0, "10G FD"},
+	}
+
+	for _, tc := range tests {
+		port, ok := dev.PortByIndex(tc.idx)
+		if !ok {
+			t.Errorf("port %d: not found", tc.idx)
+
+			continue
+		}
+
+		if port.PortIndex != tc.idx {
+			t.Errorf("port %d: got port %d", tc.idx, port.PortIndex)
+		}
+
+		if got := port.PoEStatus(); got != tc.poe {
+			t.Errorf("port %d: got PoE %s, want %s", tc.idx, got, tc.poe)
+		}
+
+		if got := port.PoEWatts(); got != tc.watts {
+			t.Errorf("port %d: got %.2fW, want %.2fW", tc.idx, got, tc.watts)
+		}
+
+		if got := port.LinkSpeed(); got != tc.speed {
+			t.Errorf("port %d: got speed %q, want %q", tc.idx, got, tc.speed)
+		}
+	}
+
+	if _, ok := dev.PortByIndex(9); ok {
+		t.Errorf("port 9: found on an 8 port switch")
+	}
+}
+
+func TestPortByIndexAddressable(t *testing.T) {
+	devices := loadDevices(t, "testdata/device_usw.json")
+	dev := &devices[0]
+
+	port, _ := dev.PortByIndex(3)
+	port.Name = "renamed"
+
+	if dev.PortTable[2].Name != "renamed" {
+		t.Errorf("PortByIndex returned a copy of the port")
+	}
+}
@@ -0,0 +1,129 @@
+package unifi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// PortOverride describes the per-port configuration of a switch.
+type PortOverride struct {
+	Name         string `json:"name,omitempty"`
+	POEMode      string `json:"poe_mode,omitempty"`
+	PortConfigID string `json:"portconf_id,omitempty"`
+	PortIndex    int64  `json:"port_idx,omitempty"`
+}
+
+// PortProfile describes a switch port profile (VLAN assignment, PoE, etc.).
+type PortProfile struct {
+	ID string `json:"_id,omitempty"`
+
+	Forward              string   `json:"forward,omitempty"`
+	Name                 string   `json:"name,omitempty"`
+	NativeNetworkConfID  string   `json:"native_networkconf_id,omitempty"`
+	POEMode              string   `json:"poe_mode,omitempty"`
+	SiteID               string   `json:"site_id,omitempty"`
+	TaggedNetworkConfIDs []string `json:"tagged_networkconf_ids,omitempty"`
+	VoiceNetworkConfID   string   `json:"voice_networkconf_id,omitempty"`
+}
+
+func (p *PortProfile) String() string {
+	return fmt.Sprintf("%-24s %-25s %-10s %s", p.ID, p.Name, p.Forward, p.NativeNetworkConfID)
+}
+
+// ListPortProfiles describes the configured switch port profiles.
+func (s *Session) ListPortProfiles() (string, error) {
+	return s.action(http.MethodGet, "/rest/portconf", nil)
+}
+
+// GetPortProfiles returns the configured switch port profiles.
+func (s *Session) GetPortProfiles() ([]PortProfile, error) {
+	var (
+		data string
+		resp PortProfileResponse
+		err  error
+	)
+
+	if data, err = s.ListPortProfiles(); err != nil {
+		return nil, fmt.Errorf("fetching port profiles: %w", err)
+	}
+
+	if err = json.Unmarshal([]byte(data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshalling port profiles: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// SetPortProfile assigns the port profile profileID to port portIdx of the
+// switch identified by switchMAC. The other port overrides of the switch, and
+// any fields of the override not managed here, are preserved.
+func (s *Session) SetPortProfile(switchMAC MAC, portIdx int64, profileID string) (string, error) {
+	if len(profileID) == 0 {
+		return "", fmt.Errorf("missing port profile id")
+	}
+
+	device, err := s.getRawDevice(switchMAC)
+	if err != nil {
+		return "", err
+	}
+
+	id, _ := device["_id"].(string)
+	if len(id) == 0 {
+		return "", fmt.Errorf("device %s has no id", switchMAC)
+	}
+
+	overrides, _ := device["port_overrides"].([]any)
+
+	found := false
+
+	for _, o := range overrides {
+		override, ok := o.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if idx, ok := override["port_idx"].(float64); ok && int64(idx) == portIdx {
+			override["portconf_id"] = profileID
+			found = true
+		}
+	}
+
+	if !found {
+		overrides = append(overrides, map[string]any{"port_idx": portIdx, "portconf_id": profileID})
+	}
+
+	payload, err := json.Marshal(map[string]any{"port_overrides": overrides})
+	if err != nil {
+		return "", fmt.Errorf("marshalling port overrides: %w", err)
+	}
+
+	return s.action(http.MethodPut, "/rest/device/"+id, bytes.NewBuffer(payload))
+}
+
+// getRawDevice returns the generic JSON representation of a single device,
+// so that fields not modeled by Device survive a read-modify-write.
+func (s *Session) getRawDevice(mac MAC) (map[string]any, error) {
+	var (
+		data string
+		resp struct {
+			Data []map[string]any `json:"data"`
+		}
+		err error
+	)
+
+	if data, err = s.action(http.MethodGet, "/stat/device/"+string(mac), nil); err != nil {
+		return nil, fmt.Errorf("fetching device %s: %w", mac, err)
+	}
+
+	if err = json.Unmarshal([]byte(data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshalling device %s: %w", mac, err)
+	}
+
+	if len(resp.Data) < 1 {
+		return nil, fmt.Errorf("zero results: %s", data)
+	}
+
+	return resp.Data[0], nil
+}
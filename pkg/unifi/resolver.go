@@ -0,0 +1,89 @@
+package unifi
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultNameCacheTTL is how long a NameResolver serves names before
+// fetching them again.
+var DefaultNameCacheTTL = 5 * time.Minute
+
+// NameResolver resolves names to MACs like Session.GetMACsBy, but serves
+// them from a copy of GetNames that is refreshed at most once per TTL.
+// GetNames fetches devices, clients, and users, so this saves three
+// controller round trips per lookup. It is safe for concurrent use, but
+// the Session it wraps is not, so callers sharing the Session must
+// serialize access to it.
+type NameResolver struct {
+	session *Session
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	names   map[string][]MAC
+	fetched time.Time
+	stats   CacheStats
+}
+
+// NewNameResolver returns a NameResolver for s; a ttl of zero or less
+// disables caching.
+func NewNameResolver(s *Session, ttl time.Duration) *NameResolver {
+	return &NameResolver{session: s, ttl: ttl}
+}
+
+// GetMACsBy is Session.GetMACsBy, from the cache.
+func (r *NameResolver) GetMACsBy(ids ...string) ([]MAC, error) {
+	names, err := r.getNames()
+	if err != nil {
+		return nil, err
+	}
+
+	return macsBy(names, ids...), nil
+}
+
+// GetMACsByID is Session.GetMACsByID, from the cache.
+func (r *NameResolver) GetMACsByID(ids ...string) (map[string][]MAC, error) {
+	names, err := r.getNames()
+	if err != nil {
+		return nil, err
+	}
+
+	return macsByID(names, ids...), nil
+}
+
+func (r *NameResolver) getNames() (map[string][]MAC, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names == nil || r.ttl <= 0 || time.Since(r.fetched) >= r.ttl {
+		r.stats.Misses++
+
+		names, err := r.session.GetNames()
+		if err != nil {
+			return nil, err
+		}
+
+		r.names, r.fetched = names, time.Now()
+	} else {
+		r.stats.Hits++
+	}
+
+	return r.names, nil
+}
+
+// Invalidate discards the cached names, e.g. after renaming a client.
+func (r *NameResolver) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.names = nil
+}
+
+// Stats returns the number of lookups served from the cache, and the
+// number that fetched the names.
+func (r *NameResolver) Stats() CacheStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.stats
+}
@@ -18,6 +18,42 @@ type EventResponse struct {
 	Data []Event `json:"data,omitempty"`
 }
 
+// PortProfileResponse encapsulates a UniFi http response.
+type PortProfileResponse struct {
+	Meta Meta          `json:"meta,omitempty"`
+	Data []PortProfile `json:"data,omitempty"`
+}
+
+// UserGroupResponse encapsulates a UniFi http response.
+type UserGroupResponse struct {
+	Meta Meta        `json:"meta,omitempty"`
+	Data []UserGroup `json:"data,omitempty"`
+}
+
+// SpeedTestResponse encapsulates a UniFi http response.
+type SpeedTestResponse struct {
+	Meta Meta              `json:"meta,omitempty"`
+	Data []SpeedTestResult `json:"data,omitempty"`
+}
+
+// GuestResponse encapsulates a UniFi http response.
+type GuestResponse struct {
+	Meta Meta                 `json:"meta,omitempty"`
+	Data []GuestAuthorization `json:"data,omitempty"`
+}
+
+// HealthResponse encapsulates a UniFi http response.
+type HealthResponse struct {
+	Meta Meta              `json:"meta,omitempty"`
+	Data []SubsystemHealth `json:"data,omitempty"`
+}
+
+// WLANResponse encapsulates a UniFi http response.
+type WLANResponse struct {
+	Meta Meta   `json:"meta,omitempty"`
+	Data []WLAN `json:"data,omitempty"`
+}
+
 // Meta encapsulates basic meta from response.
 type Meta struct {
 	RC      string `json:"rc,omitempty"`
@@ -0,0 +1,112 @@
+package unifi
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultSatisfactionWorst is how many of the worst clients and access
+// points a SatisfactionSummary usually lists.
+const DefaultSatisfactionWorst = 5
+
+// SatisfactionScore is the satisfaction (0-100) of one client or device.
+type SatisfactionScore struct {
+	Name         string `json:"name"`
+	MAC          MAC    `json:"mac"`
+	Satisfaction int64  `json:"satisfaction"`
+}
+
+// SatisfactionSummary is a network experience summary: the average
+// satisfaction of connected clients, overall and by connection type, and
+// the worst scoring clients and access points. Averages are 0 when no
+// client reports a satisfaction.
+type SatisfactionSummary struct {
+	Overall  int64 `json:"overall"`
+	Wireless int64 `json:"wireless"`
+	Wired    int64 `json:"wired"`
+
+	WorstClients      []SatisfactionScore `json:"worst_clients,omitempty"`
+	WorstAccessPoints []SatisfactionScore `json:"worst_access_points,omitempty"`
+}
+
+func (s *SatisfactionSummary) String() string {
+	return fmt.Sprintf("overall %d%%  wireless %d%%  wired %d%%", s.Overall, s.Wireless, s.Wired)
+}
+
+// GetSatisfactionSummary summarizes the satisfaction of the connected
+// clients and the access points, listing the worst of each.
+func (s *Session) GetSatisfactionSummary(worst int) (*SatisfactionSummary, error) {
+	clients, err := s.GetClients()
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := s.GetDevices(DeviceType("uap"))
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildSatisfaction(clients, devices, worst), nil
+}
+
+// BuildSatisfaction summarizes the satisfaction of clients and access
+// points, listing the worst of each. Clients and devices without a
+// reported satisfaction are left out.
+func BuildSatisfaction(clients []Client, devices []Device, worst int) *SatisfactionSummary {
+	var (
+		summary                SatisfactionSummary
+		all, wireless, wired   average
+		clientScores, apScores []SatisfactionScore
+	)
+
+	for ix := range clients {
+		c := &clients[ix]
+		if c.Satisfaction <= 0 {
+			continue
+		}
+
+		all.add(c.Satisfaction)
+
+		if c.IsWired {
+			wired.add(c.Satisfaction)
+		} else {
+			wireless.add(c.Satisfaction)
+		}
+
+		clientScores = append(clientScores, SatisfactionScore{Name: c.DisplayName(), MAC: c.MAC, Satisfaction: c.Satisfaction})
+	}
+
+	for _, d := range devices {
+		if d.Satisfaction > 0 {
+			apScores = append(apScores, SatisfactionScore{Name: d.Name, MAC: d.MAC, Satisfaction: d.Satisfaction})
+		}
+	}
+
+	summary.Overall, summary.Wireless, summary.Wired = all.value(), wireless.value(), wired.value()
+	summary.WorstClients = worstScores(clientScores, worst)
+	summary.WorstAccessPoints = worstScores(apScores, worst)
+
+	return &summary
+}
+
+func worstScores(scores []SatisfactionScore, n int) []SatisfactionScore {
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Satisfaction < scores[j].Satisfaction })
+
+	if len(scores) > n {
+		scores = scores[:n]
+	}
+
+	return scores
+}
+
+type average struct{ sum, count int64 }
+
+func (a *average) add(v int64) { a.sum, a.count = a.sum+v, a.count+1 }
+
+func (a *average) value() int64 {
+	if a.count == 0 {
+		return 0
+	}
+
+	return a.sum / a.count
+}
@@ -0,0 +1,181 @@
+// Package schema generates JSON Schema documents describing the JSON
+// representation of the unifi types.
+//
+// Property names are the `json` struct tag names, which are the field names
+// used by the UniFi API itself (e.g. "rx_bytes", not "BytesReceived").
+// Fields tagged `schema:"synthetic"` are computed by this tool rather than
+// returned by the controller, and are marked as such.
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document.
+type Schema map[string]any
+
+// For returns the JSON Schema describing the JSON form of v.
+func For(v any) Schema {
+	g := &generator{defs: map[string]Schema{}}
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var root Schema
+	if t.Kind() == reflect.Struct && t != timeType {
+		root = g.properties(t)
+	} else {
+		root = g.schema(t)
+	}
+
+	root["$schema"] = draft
+	root["title"] = t.Name()
+
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+
+	return root
+}
+
+type generator struct {
+	defs map[string]Schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// timestamper is implemented by the integer timestamp types (e.g.
+// unifi.TimeStamp), which are numbers as the API returns them, or RFC 3339
+// strings when output is written with RFC 3339 times.
+type timestamper interface{ Time() time.Time }
+
+var timestamperType = reflect.TypeOf((*timestamper)(nil)).Elem()
+
+func (g *generator) schema(t reflect.Type) Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return Schema{"type": "string", "format": "date-time"}
+	}
+
+	if t.Kind() == reflect.Int64 && t.Implements(timestamperType) {
+		return Schema{
+			"anyOf": []Schema{
+				{"type": "integer", "description": "seconds or milliseconds since the epoch"},
+				{"type": "string", "format": "date-time"},
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.ref(t)
+	default:
+		return Schema{}
+	}
+}
+
+// ref returns a reference to the definition of the named struct t.
+func (g *generator) ref(t reflect.Type) Schema {
+	name := t.Name()
+	if _, ok := g.defs[name]; !ok {
+		g.defs[name] = Schema{} // guard against recursion
+		g.defs[name] = g.properties(t)
+	}
+
+	return Schema{"$ref": "#/$defs/" + name}
+}
+
+func (g *generator) properties(t reflect.Type) Schema {
+	props := Schema{}
+	g.fields(t, props, map[string]bool{})
+
+	return Schema{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": true,
+	}
+}
+
+// fields collects the properties of t into props. Fields of embedded structs
+// are promoted unless shadowed by an outer field, matching encoding/json.
+func (g *generator) fields(t reflect.Type, props Schema, outer map[string]bool) {
+	var embedded []reflect.Type
+
+	for ix := 0; ix < t.NumField(); ix++ {
+		f := t.Field(ix)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, ok := JSONName(f)
+		if !ok {
+			continue
+		}
+
+		if f.Anonymous && len(name) == 0 && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, f.Type)
+
+			continue
+		}
+
+		if len(name) == 0 {
+			name = f.Name
+		}
+
+		if outer[name] {
+			continue
+		}
+
+		outer[name] = true
+
+		prop := g.schema(f.Type)
+		if IsSynthetic(f) {
+			prop["description"] = "synthetic: computed locally, not returned by UniFi"
+		}
+
+		props[name] = prop
+	}
+
+	for _, e := range embedded {
+		g.fields(e, props, outer)
+	}
+}
+
+// JSONName returns the name in f's json tag, which is empty for an untagged
+// field, and false if f is left out of the JSON form.
+func JSONName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+
+	return name, true
+}
+
+// IsSynthetic reports whether f is computed locally rather than returned by
+// the controller.
+func IsSynthetic(f reflect.StructField) bool { return f.Tag.Get("schema") == "synthetic" }
@@ -16,6 +16,8 @@ import (
 
 	"github.com/jw4/x/stringset"
 	"github.com/jw4/x/transport"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Session wraps metadata to manage session state.
@@ -29,12 +31,29 @@ type Session struct {
 	login  func() (string, error)
 	err    error
 
+	tracer   trace.Tracer
+	traceCtx context.Context
+
 	nonUDMPro bool
 	site      string
 
-	outWriter io.Writer
-	errWriter io.Writer
-	dbgWriter io.Writer
+	maxClockSkew time.Duration
+	skewWarned   bool
+
+	skipIfSatisfied bool
+
+	timingWriter io.Writer
+
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+
+	stats SessionStats
+
+	outWriter  io.Writer
+	errWriter  io.Writer
+	infoWriter io.Writer
+	dbgWriter  io.Writer
 }
 
 // Option describes an option parameter.
@@ -42,6 +61,10 @@ type Option func(*Session)
 
 func WithOut(o io.Writer) Option { return func(s *Session) { s.outWriter = o } }
 func WithErr(e io.Writer) Option { return func(s *Session) { s.errWriter = e } }
+
+// WithInfo sets where informational messages go; the default is the error writer.
+func WithInfo(i io.Writer) Option { return func(s *Session) { s.infoWriter = i } }
+
 func WithDbg(d io.Writer) Option { return func(s *Session) { s.dbgWriter = d } }
 
 // Initialize prepares the session for use.
@@ -52,15 +75,24 @@ func (s *Session) Initialize(options ...Option) error {
 
 	s.outWriter = os.Stdout
 	s.errWriter = os.Stderr
+	s.maxClockSkew = DefaultMaxClockSkew
 
 	for _, option := range options {
 		option(s)
 	}
 
+	if s.infoWriter == nil {
+		s.infoWriter = s.errWriter
+	}
+
 	s.err = nil
 
 	if len(s.Endpoint) == 0 {
 		s.setErrorString("missing endpoint")
+	} else if endpoint, err := normalizeEndpoint(s.Endpoint); err != nil {
+		s.setError(err)
+	} else {
+		s.Endpoint = endpoint
 	}
 
 	if len(s.Username) == 0 {
@@ -78,8 +110,8 @@ func (s *Session) Initialize(options ...Option) error {
 
 	s.client = &http.Client{ // nolint:exhaustivestruct
 		Jar:       jar,
-		Timeout:   time.Minute * 1,
-		Transport: transport.NewLoggingTransport(http.DefaultTransport, transport.LoggingOutput(s.dbgWriter)),
+		Timeout:   requestTimeout,
+		Transport: transport.NewLoggingTransport(s.transport(http.DefaultTransport), transport.LoggingOutput(s.dbgWriter)),
 	}
 
 	s.login = s.webLogin
@@ -99,8 +131,88 @@ func (s *Session) Login() (string, error) {
 	return s.login()
 }
 
-// GetDevices looks up and returns known Devices.
-func (s *Session) GetDevices() ([]Device, error) {
+// Clone returns an independent Session with the same endpoint, credentials,
+// and options, but with its own cookie jar, CSRF token, and error state.
+// The underlying transport is shared. Session is not safe for concurrent
+// use, so Clone is the way to get one Session per goroutine.
+//
+// The clone is not authenticated; call Login before using it.
+func (s *Session) Clone() *Session {
+	c := &Session{
+		Endpoint: s.Endpoint,
+		Username: s.Username,
+		Password: s.Password,
+
+		nonUDMPro: s.nonUDMPro,
+		site:      s.site,
+
+		maxClockSkew: s.maxClockSkew,
+		skewWarned:   s.skewWarned,
+
+		skipIfSatisfied: s.skipIfSatisfied,
+
+		timingWriter: s.timingWriter,
+
+		dialTimeout:           s.dialTimeout,
+		tlsHandshakeTimeout:   s.tlsHandshakeTimeout,
+		responseHeaderTimeout: s.responseHeaderTimeout,
+
+		outWriter:  s.outWriter,
+		errWriter:  s.errWriter,
+		infoWriter: s.infoWriter,
+		dbgWriter:  s.dbgWriter,
+
+		tracer: s.tracer,
+	}
+
+	if s.client == nil {
+		return c
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		c.setError(err)
+	}
+
+	c.client = &http.Client{ // nolint:exhaustivestruct
+		Jar:       jar,
+		Timeout:   s.client.Timeout,
+		Transport: s.client.Transport,
+	}
+
+	c.login = c.webLogin
+
+	return c
+}
+
+// Err returns the error that stopped the session, if any. Once set, every
+// later call fails with it.
+func (s *Session) Err() error { return s.err }
+
+// Relogin clears the error that stopped the session and logs in again with
+// a fresh cookie jar, so that a long-running caller can recover from a
+// transient failure without restarting. Unlike Clone, the session keeps its
+// stats and identity, so anything holding it sees the recovered session.
+func (s *Session) Relogin() (string, error) {
+	if s.client == nil {
+		return "", ErrUninitializedSession
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return "", err
+	}
+
+	s.client.Jar = jar
+	s.csrf = ""
+	s.err = nil
+	s.login = s.webLogin
+
+	return s.Login()
+}
+
+// GetDevices looks up and returns known Devices that pass all filters.
+func (s *Session) GetDevices(filters ...DeviceFilter) ([]Device, error) {
 	var (
 		devices []Device
 		dmap    map[string]Device
@@ -108,12 +220,17 @@ func (s *Session) GetDevices() ([]Device, error) {
 		err error
 	)
 
+	_, end := s.startSpan("GetDevices")
+	defer func() { end(&err) }()
+
 	if dmap, err = s.getDevices(); err != nil {
 		return nil, fmt.Errorf("getting devices: %w", err)
 	}
 
 	for _, device := range dmap {
-		devices = append(devices, device)
+		if passAllDevices(device, filters...) {
+			devices = append(devices, device)
+		}
 	}
 
 	DeviceDefault.Sort(devices)
@@ -152,6 +269,9 @@ func (s *Session) GetMACs() (map[MAC][]string, error) {
 		err error
 	)
 
+	_, end := s.startSpan("GetMACs")
+	defer func() { end(&err) }()
+
 	if devices, err = s.GetDevices(); err != nil {
 		return nil, fmt.Errorf("getting devices: %w", err)
 	}
@@ -215,6 +335,9 @@ func (s *Session) GetNames() (map[string][]MAC, error) { // nolint:funlen
 		err error
 	)
 
+	_, end := s.startSpan("GetNames")
+	defer func() { end(&err) }()
+
 	if devices, err = s.GetDevices(); err != nil {
 		return nil, fmt.Errorf("getting devices: %w", err)
 	}
@@ -268,40 +391,94 @@ func (s *Session) GetNames() (map[string][]MAC, error) { // nolint:funlen
 
 	ret := map[string][]MAC{}
 	for name, m := range names {
-		vals := m.Values()
-		macs := make([]MAC, len(vals))
-		for ix, val := range vals {
-			macs[ix] = MAC(val)
-		}
-
-		ret[name] = macs
+		ret[name] = toMACs(m.Values())
 	}
 
 	return ret, nil
 }
 
+// GetMACsBy returns the MACs of the clients and devices with any of the
+// given names, IPs, or MACs, without duplicates, in the order first matched.
 func (s *Session) GetMACsBy(ids ...string) ([]MAC, error) {
 	var (
-		err     error
-		allMACs []MAC
-		names   map[string][]MAC
+		err   error
+		names map[string][]MAC
 	)
 
 	if names, err = s.GetNames(); err != nil {
 		return nil, err
 	}
 
+	return macsBy(names, ids...), nil
+}
+
+// GetMACsByID is GetMACsBy, but reports which MACs each id matched, so
+// callers can tell when one id matches several clients. Ids that match
+// nothing are left out.
+func (s *Session) GetMACsByID(ids ...string) (map[string][]MAC, error) {
+	var (
+		err   error
+		names map[string][]MAC
+	)
+
+	if names, err = s.GetNames(); err != nil {
+		return nil, err
+	}
+
+	return macsByID(names, ids...), nil
+}
+
+func macsBy(names map[string][]MAC, ids ...string) []MAC {
+	var set stringset.OrderedStringSet
+
+	for _, id := range ids {
+		for _, mac := range names[id] {
+			set.Add(mac.String())
+		}
+	}
+
+	return toMACs(set.Values())
+}
+
+func macsByID(names map[string][]MAC, ids ...string) map[string][]MAC {
+	matched := map[string][]MAC{}
+
 	for _, id := range ids {
 		if macs, ok := names[id]; ok {
-			allMACs = append(allMACs, macs...)
+			matched[id] = macs
 		}
 	}
 
-	return allMACs, nil
+	return matched
 }
 
-// Raw executes arbitrary endpoints.
+func toMACs(vals []string) []MAC {
+	if len(vals) == 0 {
+		return nil
+	}
+
+	macs := make([]MAC, len(vals))
+	for ix, val := range vals {
+		macs[ix] = MAC(val)
+	}
+
+	return macs
+}
+
+// Raw executes arbitrary endpoints below the site API, e.g. /stat/health,
+// with GET, POST, PUT, or DELETE. The path must start with a slash and may
+// not step out of the site with ".." segments.
 func (s *Session) Raw(method, path string, body io.Reader) (string, error) {
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("raw path %q must start with /", path)
+	}
+
+	for _, segment := range strings.Split(strings.SplitN(path, "?", 2)[0], "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("raw path %q may not contain ..", path)
+		}
+	}
+
 	return s.action(method, path, body)
 }
 
@@ -357,23 +534,23 @@ func (s *Session) Unblock(macs ...MAC) (string, error) { return s.macsAction("un
 func (s *Session) Forget(macs ...MAC) (string, error) { return s.macsAction("forget-sta", macs) }
 
 // KickFn uses Clients to find MAC addresses to Kick.
-func (s *Session) KickFn(clients []Client, keys map[string]bool) {
-	s.clientsFn(s.Kick, keys, clients...)
+func (s *Session) KickFn(clients []Client, keys map[string]bool) BatchResult {
+	return s.clientsFn("kick", s.Kick, keys, clients...)
 }
 
 // BlockFn uses Clients to find MAC addresses to Block.
-func (s *Session) BlockFn(clients []Client, keys map[string]bool) {
-	s.clientsFn(s.Block, keys, clients...)
+func (s *Session) BlockFn(clients []Client, keys map[string]bool) BatchResult {
+	return s.clientsFn("block", s.Block, keys, clients...)
 }
 
 // UnblockFn uses Clients to find MAC addresses to Unblock.
-func (s *Session) UnblockFn(clients []Client, keys map[string]bool) {
-	s.clientsFn(s.Unblock, keys, clients...)
+func (s *Session) UnblockFn(clients []Client, keys map[string]bool) BatchResult {
+	return s.clientsFn("unblock", s.Unblock, keys, clients...)
 }
 
 // ForgetFn uses Clients to find MAC addresses to Forget.
-func (s *Session) ForgetFn(clients []Client, keys map[string]bool) {
-	s.clientsFn(s.Forget, keys, clients...)
+func (s *Session) ForgetFn(clients []Client, keys map[string]bool) BatchResult {
+	return s.clientsFn("forget", s.Forget, keys, clients...)
 }
 
 // getUserByMac looks up a Client by the MAC address.
@@ -407,6 +584,34 @@ func Blocked(c Client) bool    { return c.IsBlocked }
 func Authorized(c Client) bool { return c.IsAuthorized }
 func Guest(c Client) bool      { return c.IsGuest }
 func Wired(c Client) bool      { return c.IsWired }
+func Active(c Client) bool     { return c.IsActive() }
+
+// InSubnet matches clients whose display IP, as o prefers, is within
+// subnet.
+func InSubnet(subnet Subnet, o DisplayOptions) ClientFilter {
+	return func(c Client) bool { return subnet.Contains(IP(c.DisplayIP(o))) }
+}
+
+// UptimeAtLeast matches clients that have been connected for at least d.
+func UptimeAtLeast(d time.Duration) ClientFilter {
+	return func(c Client) bool { return time.Duration(c.Uptime)*time.Second >= d }
+}
+
+// FirstSeenBefore matches clients first seen before t. Clients without a
+// first seen time don't match.
+func FirstSeenBefore(t time.Time) ClientFilter {
+	return func(c Client) bool { return c.FirstSeen > 0 && c.FirstSeen.Time().Before(t) }
+}
+
+// NewClient matches clients first seen within d of when the filter was
+// created. Clients without a first seen time don't match.
+func NewClient(within time.Duration) ClientFilter { return newClientAt(within, time.Now()) }
+
+func newClientAt(within time.Duration, now time.Time) ClientFilter {
+	since := now.Add(-within)
+
+	return func(c Client) bool { return c.FirstSeen > 0 && !c.FirstSeen.Time().Before(since) }
+}
 
 func passAll(client Client, filters ...ClientFilter) bool {
 	for _, filter := range filters {
@@ -418,47 +623,89 @@ func passAll(client Client, filters ...ClientFilter) bool {
 	return true
 }
 
+type DeviceFilter func(Device) bool
+
+func NotDevice(filter DeviceFilter) DeviceFilter { return func(d Device) bool { return !filter(d) } }
+
+func DeviceAdopted(d Device) bool { return d.IsAdopted }
+
+// DeviceOffline matches devices the controller has lost contact with (see
+// DeviceState.IsOffline).
+func DeviceOffline(d Device) bool { return d.ConnectionState().IsOffline() }
+
+// DeviceModel matches devices whose model matches the case-insensitive
+// glob, e.g. "U6*".
+func DeviceModel(glob string) DeviceFilter {
+	return func(d Device) bool { return MatchAny([]string{glob}, d.Model) }
+}
+
+// DeviceType matches devices of the given type, e.g. "uap", "usw", or "ugw".
+func DeviceType(typ string) DeviceFilter {
+	return func(d Device) bool { return strings.EqualFold(d.DeviceType, typ) }
+}
+
+func passAllDevices(device Device, filters ...DeviceFilter) bool {
+	for _, filter := range filters {
+		if !filter(device) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // getClients returns a list of clients.  If all is false, only the active
 // clients will be returned, otherwise all the known clients will be returned.
 func (s *Session) getClients(all bool, filters ...ClientFilter) ([]Client, error) {
 	var (
 		devices map[string]Device
-
-		clientsJSON string
-		clients     []Client
-		cresp       ClientResponse
+		clients []Client
 
 		err error
 	)
 
+	_, end := s.startSpan(clientsSpanName(all))
+	defer func() { end(&err) }()
+
 	sorter := ClientDefault
-	fetch := s.ListClients
+	path := "/stat/sta"
 
 	if all {
 		sorter = ClientHistorical
-		fetch = s.ListUsers
+		path = "/rest/user"
 	}
 
+	// Devices only supply the upstream names, so the clients are still
+	// returned without them if the devices can't be fetched, even if the
+	// failure would otherwise have stopped the session.
+	stopped := s.err
 	if devices, err = s.getDevices(); err != nil {
-		return nil, fmt.Errorf("getting devices: %w", err)
-	}
+		s.err = stopped
 
-	if clientsJSON, err = fetch(); err != nil {
-		return nil, fmt.Errorf("listing clients: %w", err)
+		fmt.Fprintf(s.infoWriter, "warning: getting devices, upstream names are missing: %v\n", err)
 	}
 
-	if err = json.Unmarshal([]byte(clientsJSON), &cresp); err != nil {
-		return nil, fmt.Errorf("unmarshalling clients: %w", err)
-	}
+	// The /rest/user response can be tens of megabytes, so clients are
+	// decoded one at a time and only those passing the filters are kept.
+	if err = s.actionStream(path, func(r io.Reader) error {
+		return decodeData(r, func(dec *json.Decoder) error {
+			var client Client
+			if err := dec.Decode(&client); err != nil {
+				return err
+			}
 
-	for _, client := range cresp.Data {
-		if dev, ok := devices[client.UpstreamMAC()]; ok {
-			client.UpstreamName = dev.Name
-		}
+			if dev, ok := devices[client.UpstreamMAC()]; ok {
+				client.UpstreamName = dev.Name
+			}
 
-		if passAll(client, filters...) {
-			clients = append(clients, client)
-		}
+			if passAll(client, filters...) {
+				clients = append(clients, client)
+			}
+
+			return nil
+		})
+	}); err != nil {
+		return nil, fmt.Errorf("listing clients: %w", err)
 	}
 
 	sorter.Sort(clients)
@@ -501,6 +748,9 @@ func (s *Session) getEvents(all bool) ([]Event, error) {
 		err error
 	)
 
+	_, end := s.startSpan(eventsSpanName(all))
+	defer func() { end(&err) }()
+
 	fetch := s.ListEvents
 	if all {
 		fetch = s.ListAllEvents
@@ -522,11 +772,14 @@ func (s *Session) getEvents(all bool) ([]Event, error) {
 }
 
 // webLogin performs the authentication for this session.
-func (s *Session) webLogin() (string, error) {
+func (s *Session) webLogin() (_ string, err error) {
 	if s.err != nil {
 		return "", s.err
 	}
 
+	_, end := s.startSpan("Login")
+	defer func() { end(&err) }()
+
 	u, err := url.Parse(fmt.Sprintf("%s/api/auth/login", s.Endpoint))
 	if err != nil {
 		s.setError(err)
@@ -544,6 +797,32 @@ func (s *Session) webLogin() (string, error) {
 	return respBody, err
 }
 
+// normalizeEndpoint defaults the scheme to https and trims trailing
+// slashes, so paths can be appended directly. Plain http is rejected since
+// the credentials would be sent in the clear.
+func normalizeEndpoint(endpoint string) (string, error) {
+	endpoint = strings.TrimSpace(endpoint)
+
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	if u.Scheme != "https" {
+		return "", fmt.Errorf("invalid endpoint %q: scheme must be https", endpoint)
+	}
+
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid endpoint %q: missing host", endpoint)
+	}
+
+	return strings.TrimRight(endpoint, "/"), nil
+}
+
 // buildURL generates the endpoint URL relevant to the configured
 // version of UniFi.
 func (s *Session) buildURL(path string) (*url.URL, error) {
@@ -587,23 +866,24 @@ func (s *Session) macsAction(action string, macs []MAC) (string, error) {
 	return s.action(http.MethodPost, "/cmd/stamgr", bytes.NewBufferString(payload))
 }
 
-func (s *Session) clientsFn(action func(...MAC) (string, error), keys map[string]bool, clients ...Client) {
-	var macs []MAC
+// clientsFn applies fn to the clients named in keys, reporting errors to
+// the error writer, and returns the per-client outcome.
+func (s *Session) clientsFn(action string, fn func(...MAC) (string, error), keys map[string]bool, clients ...Client) BatchResult {
+	result := BatchResult{Action: action}
+
 	for _, client := range clients {
 		display := firstNonEmpty(client.Name, client.Hostname)
 		if k, ok := keys[display]; ok && k {
-			macs = append(macs, client.MAC)
+			result.Affected = append(result.Affected, ActionResult{MAC: client.MAC, Name: display})
 		}
 	}
 
-	res, err := action(macs...)
+	result, err := s.ApplyBatch(result, fn)
 	if err != nil {
-		fmt.Fprintf(s.errWriter, "%s\nerror: %v\n", res, err)
-
-		return
+		fmt.Fprintf(s.errWriter, "error: %s: %v\n", action, err)
 	}
 
-	fmt.Fprintf(s.outWriter, "%s\n", res)
+	return result
 }
 
 func (s *Session) setUserDetails(id, name, ip string) (string, error) {
@@ -633,6 +913,89 @@ func (s *Session) setUserDetails(id, name, ip string) (string, error) {
 	return s.action(http.MethodPut, "/rest/user/"+id, &buf)
 }
 
+// actionStream GETs path and hands the response body to read.
+func (s *Session) actionStream(path string, read func(io.Reader) error) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	u, err := s.buildURL(path)
+	if err != nil {
+		s.setError(err)
+
+		return s.err
+	}
+
+	_, err = s.verbStream(http.MethodGet, u, nil, read)
+
+	return err
+}
+
+// decodeData walks a UniFi response object, calling each with the decoder
+// positioned at every element of its "data" array in turn. Other keys are
+// skipped.
+func decodeData(r io.Reader, each func(*json.Decoder) error) error {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+
+		if key != "data" {
+			var skip json.RawMessage
+			if err = dec.Decode(&skip); err != nil {
+				return fmt.Errorf("decoding response %v: %w", key, err)
+			}
+
+			continue
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("decoding response data: %w", err)
+		}
+
+		if tok == nil {
+			continue
+		}
+
+		if tok != json.Delim('[') {
+			return fmt.Errorf("decoding response data: expected an array, got %v", tok)
+		}
+
+		for dec.More() {
+			if err = each(dec); err != nil {
+				return fmt.Errorf("decoding response data: %w", err)
+			}
+		}
+
+		if err = expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	if tok != want {
+		return fmt.Errorf("decoding response: expected %q, got %v", want, tok)
+	}
+
+	return nil
+}
+
 func (s *Session) action(method, path string, body io.Reader) (string, error) {
 	if s.err != nil {
 		return "", s.err
@@ -652,6 +1015,8 @@ func (s *Session) action(method, path string, body io.Reader) (string, error) {
 		return s.post(u, body)
 	case http.MethodPut:
 		return s.put(u, body)
+	case http.MethodDelete:
+		return s.del(u)
 	default:
 		return "", fmt.Errorf("unconfigured method: %q", method)
 	}
@@ -669,8 +1034,46 @@ func (s *Session) put(u fmt.Stringer, body io.Reader) (string, error) {
 	return s.verb("PUT", u, body)
 }
 
+func (s *Session) del(u fmt.Stringer) (string, error) {
+	return s.verb("DELETE", u, nil)
+}
+
 func (s *Session) verb(verb string, u fmt.Stringer, body io.Reader) (string, error) {
-	req, err := http.NewRequestWithContext(context.Background(), verb, u.String(), body)
+	var out string
+
+	errBody, err := s.verbStream(verb, u, body, func(r io.Reader) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			s.setError(err)
+
+			return s.err
+		}
+
+		out = string(b)
+
+		return nil
+	})
+	if errBody != "" {
+		return errBody, err
+	}
+
+	return out, err
+}
+
+// verbStream performs the request and hands a successful response body to
+// read, so large responses need not be held in memory. Unsuccessful
+// response bodies are read in full and returned, as with verb.
+func (s *Session) verbStream(verb string, u fmt.Stringer, body io.Reader, read func(io.Reader) error) (_ string, err error) {
+	ctx, end := s.startSpan("HTTP "+verb, attribute.String("http.request.method", verb), attribute.String("url.full", u.String()))
+	defer func() {
+		s.recordRequest(err)
+		end(&err)
+	}()
+
+	ctx, timed := s.timeRequest(ctx, verb, u)
+	defer timed()
+
+	req, err := http.NewRequestWithContext(ctx, verb, u.String(), body)
 	if err != nil {
 		s.setError(err)
 
@@ -686,18 +1089,32 @@ func (s *Session) verb(verb string, u fmt.Stringer, body io.Reader) (string, err
 		req.Header.Set("x-csrf-token", s.csrf)
 	}
 
+	sent := time.Now()
+
 	resp, err := s.client.Do(req)
 	if err != nil {
-		s.setError(err)
+		s.setError(clockHint(err))
 
 		return "", s.err
 	}
 	defer resp.Body.Close()
 
+	s.checkClockSkew(resp.Header.Get("Date"), sent)
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
 	if tok := resp.Header.Get("x-csrf-token"); tok != "" {
 		s.csrf = tok
 	}
 
+	if http.StatusOK <= resp.StatusCode && resp.StatusCode < http.StatusBadRequest {
+		if err = read(resp.Body); err != nil {
+			return "", err
+		}
+
+		return "", s.err
+	}
+
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		s.setError(err)
@@ -705,17 +1122,17 @@ func (s *Session) verb(verb string, u fmt.Stringer, body io.Reader) (string, err
 		return "", s.err
 	}
 
-	if resp.StatusCode < http.StatusOK || http.StatusBadRequest <= resp.StatusCode {
-		if resp.StatusCode == http.StatusUnauthorized {
-			fmt.Fprintf(s.errWriter, "\nlogged out; re-authenticating\n")
-			s.login = s.webLogin
-			if r, err := s.login(); err != nil {
-				s.setError(err)
-				return r, fmt.Errorf("login attempt failed: %w", err)
-			}
-		} else {
-			return string(respBody), fmt.Errorf("http error: %s", resp.Status)
-		}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return string(respBody), fmt.Errorf("http error: %s", resp.Status)
+	}
+
+	fmt.Fprintf(s.infoWriter, "\nlogged out; re-authenticating\n")
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("unifi.reauthenticated", true))
+	s.stats.Reauthentications++
+	s.login = s.webLogin
+	if r, err := s.login(); err != nil {
+		s.setError(err)
+		return r, fmt.Errorf("login attempt failed: %w", err)
 	}
 
 	return string(respBody), s.err
@@ -0,0 +1,123 @@
+package unifi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestSession returns a session for a TLS test server, treated as
+// already logged in.
+func newTestSession(t *testing.T, handler http.Handler, options ...Option) *Session {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	s := &Session{Endpoint: srv.URL, Username: "admin", Password: "secret"}
+	options = append([]Option{WithOut(io.Discard), WithErr(io.Discard), WithMaxClockSkew(-1)}, options...)
+	if err := s.Initialize(options...); err != nil {
+		t.Fatal(err)
+	}
+
+	s.client = srv.Client()
+	s.login = func() (string, error) { return "", nil }
+
+	return s
+}
+
+func TestClientAgeFilters(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	epoch := TimeStamp(now.Unix())
+
+	tests := []struct {
+		name   string
+		filter ClientFilter
+		client Client
+		want   bool
+	}{
+		{"uptime longer", UptimeAtLeast(time.Hour), Client{Uptime: 3601}, true},
+		{"uptime exactly", UptimeAtLeast(time.Hour), Client{Uptime: 3600}, true},
+		{"uptime shorter", UptimeAtLeast(time.Hour), Client{Uptime: 3599}, false},
+		{"uptime unset", UptimeAtLeast(time.Second), Client{}, false},
+		{"uptime zero duration", UptimeAtLeast(0), Client{}, true},
+
+		{"first seen before", FirstSeenBefore(now), Client{FirstSeen: epoch - 1}, true},
+		{"first seen at", FirstSeenBefore(now), Client{FirstSeen: epoch}, false},
+		{"first seen after", FirstSeenBefore(now), Client{FirstSeen: epoch + 1}, false},
+		{"first seen unset", FirstSeenBefore(now), Client{}, false},
+
+		{"new within", newClientAt(24*time.Hour, now), Client{FirstSeen: epoch - 3600}, true},
+		{"new at the boundary", newClientAt(24*time.Hour, now), Client{FirstSeen: epoch - 86400}, true},
+		{"new just outside", newClientAt(24*time.Hour, now), Client{FirstSeen: epoch - 86401}, false},
+		{"new in the future", newClientAt(24*time.Hour, now), Client{FirstSeen: epoch + 60}, true},
+		{"new unset", newClientAt(24*time.Hour, now), Client{}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter(tc.client); got != tc.want {
+				t.Errorf("got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetClientsWithoutDevices(t *testing.T) {
+	var info, errs bytes.Buffer
+
+	ses := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/stat/device"):
+			// Drop the connection, a network error that stops the session.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		case strings.HasSuffix(r.URL.Path, "/stat/sta"):
+			fmt.Fprint(w, `{"meta":{"rc":"ok"},"data":[{"mac":"aa:bb:cc:00:00:01","name":"laptop","uptime":60}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}), WithErr(&errs), WithInfo(&info))
+
+	clients, err := ses.GetClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clients) != 1 || clients[0].Name != "laptop" {
+		t.Errorf("got %+v, want the laptop", clients)
+	}
+
+	if !strings.Contains(info.String(), "upstream names are missing") {
+		t.Errorf("no warning in the informational output %q", info.String())
+	}
+
+	if errs.Len() > 0 {
+		t.Errorf("unexpected error output %q", errs.String())
+	}
+}
+
+func TestRawDelete(t *testing.T) {
+	var got string
+
+	ses := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Method + " " + r.URL.Path
+
+		fmt.Fprint(w, `{"meta":{"rc":"ok"},"data":[]}`)
+	}))
+
+	if _, err := ses.Raw(http.MethodDelete, "/rest/user/abc", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(got, "DELETE /") || !strings.HasSuffix(got, "/rest/user/abc") {
+		t.Errorf("got request %q, want DELETE of the user", got)
+	}
+}
@@ -0,0 +1,120 @@
+package unifi
+
+import "sort"
+
+// Signal quality buckets, from the client signal in dBm.
+const (
+	SignalExcellent = "excellent" // above -50
+	SignalGood      = "good"      // -67 to -50
+	SignalFair      = "fair"      // -75 to -67
+	SignalPoor      = "poor"      // below -75
+)
+
+// SignalQualities lists the signal quality buckets, best first.
+var SignalQualities = []string{SignalExcellent, SignalGood, SignalFair, SignalPoor}
+
+var signalRanges = map[string]string{
+	SignalExcellent: "> -50 dBm",
+	SignalGood:      "-67 to -50 dBm",
+	SignalFair:      "-75 to -67 dBm",
+	SignalPoor:      "< -75 dBm",
+}
+
+// SignalQuality returns the bucket for a signal in dBm.
+func SignalQuality(dBm int64) string {
+	switch {
+	case dBm > -50:
+		return SignalExcellent
+	case dBm >= -67:
+		return SignalGood
+	case dBm >= -75:
+		return SignalFair
+	default:
+		return SignalPoor
+	}
+}
+
+// Band returns the radio band of a wireless client, e.g. "5GHz", or the
+// radio name if it is not one of the known ones.
+func (client *Client) Band() string {
+	switch client.Radio {
+	case "ng":
+		return "2.4GHz"
+	case "na":
+		return "5GHz"
+	case "6e":
+		return "6GHz"
+	case "":
+		return "unknown"
+	default:
+		return client.Radio
+	}
+}
+
+// SignalBucket counts the clients in one signal quality bucket, overall
+// and by band.
+type SignalBucket struct {
+	Quality string         `json:"quality"`
+	Range   string         `json:"range"`
+	Count   int            `json:"count"`
+	Bands   map[string]int `json:"bands,omitempty"`
+}
+
+// SignalDistribution is the number of connected wireless clients in each
+// signal quality bucket, best first. Bands lists the bands seen.
+type SignalDistribution struct {
+	Clients int            `json:"clients"`
+	Bands   []string       `json:"bands,omitempty"`
+	Buckets []SignalBucket `json:"buckets"`
+}
+
+// GetSignalDistribution buckets the connected wireless clients by signal
+// strength.
+func (s *Session) GetSignalDistribution() (*SignalDistribution, error) {
+	clients, err := s.GetClients(Not(Wired))
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildSignalDistribution(clients), nil
+}
+
+// BuildSignalDistribution buckets the wireless clients by signal strength.
+// Wired clients and clients that don't report a signal are left out.
+func BuildSignalDistribution(clients []Client) *SignalDistribution {
+	dist := &SignalDistribution{}
+	buckets := map[string]*SignalBucket{}
+
+	for _, quality := range SignalQualities {
+		dist.Buckets = append(dist.Buckets, SignalBucket{Quality: quality, Range: signalRanges[quality], Bands: map[string]int{}})
+	}
+
+	for ix := range dist.Buckets {
+		buckets[dist.Buckets[ix].Quality] = &dist.Buckets[ix]
+	}
+
+	bands := map[string]bool{}
+
+	for ix := range clients {
+		c := &clients[ix]
+		if c.IsWired || c.Signal == 0 {
+			continue
+		}
+
+		band := c.Band()
+		bands[band] = true
+
+		bucket := buckets[SignalQuality(c.Signal)]
+		bucket.Count++
+		bucket.Bands[band]++
+		dist.Clients++
+	}
+
+	for band := range bands {
+		dist.Bands = append(dist.Bands, band)
+	}
+
+	sort.Strings(dist.Bands)
+
+	return dist
+}
@@ -0,0 +1,104 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunSpeedTest(t *testing.T) {
+	saved := SpeedTestPollInterval
+	SpeedTestPollInterval = time.Millisecond
+
+	t.Cleanup(func() { SpeedTestPollInterval = saved })
+
+	// The controller's clock is far behind the local one, so its run dates
+	// are all in the local past.
+	const previous, next = 1_000_000, 1_000_500
+
+	tests := []struct {
+		name     string
+		before   []SpeedTestResult // none if a test has never run
+		statuses []SpeedTestResult // after the test is triggered; the last repeats
+		want     int64
+	}{
+		{
+			name:   "completes",
+			before: []SpeedTestResult{{RunDate: previous, StatusDown: 2, StatusUp: 2, StatusSummary: 2}},
+			statuses: []SpeedTestResult{
+				{RunDate: previous, StatusDown: 2, StatusUp: 2, StatusSummary: 2},
+				{RunDate: previous, StatusDown: 1, StatusUp: 1, StatusSummary: 1},
+				{RunDate: next, StatusDown: 2, StatusUp: 1, StatusSummary: 1},
+				{RunDate: next, StatusDown: 2, StatusUp: 2, StatusSummary: 2, Download: 900},
+			},
+			want: next,
+		},
+		{
+			name: "first run",
+			statuses: []SpeedTestResult{
+				{RunDate: next, StatusDown: 2, StatusUp: 2, StatusSummary: 2, Download: 900},
+			},
+			want: next,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mu        sync.Mutex
+				triggered bool
+				polls     int
+			)
+
+			ses := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Cmd string `json:"cmd"`
+				}
+
+				_ = json.NewDecoder(r.Body).Decode(&body)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch {
+				case !strings.HasSuffix(r.URL.Path, "/cmd/devmgr"):
+					http.NotFound(w, r)
+				case body.Cmd == "speedtest":
+					triggered = true
+
+					fmt.Fprint(w, `{"meta":{"rc":"ok"},"data":[]}`)
+				case body.Cmd == "speedtest-status" && !triggered:
+					_ = json.NewEncoder(w).Encode(SpeedTestResponse{Data: tc.before})
+				case body.Cmd == "speedtest-status":
+					status := tc.statuses[min(polls, len(tc.statuses)-1)]
+					polls++
+
+					_ = json.NewEncoder(w).Encode(SpeedTestResponse{Data: []SpeedTestResult{status}})
+				default:
+					http.Error(w, "unexpected command", http.StatusBadRequest)
+				}
+			}))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := ses.RunSpeedTest(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.RunDate != tc.want || result.Running() {
+				t.Errorf("got %+v, want the completed run of %d", result, tc.want)
+			}
+
+			if polls != len(tc.statuses) {
+				t.Errorf("returned after %d polls, want %d", polls, len(tc.statuses))
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package unifi
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClientState describes whether a Client is currently connected.
+type ClientState int
+
+const (
+	// ClientStateOffline clients have not been seen for longer than ClientIdleAfter.
+	ClientStateOffline ClientState = iota
+	// ClientStateIdle clients were seen recently, but are not connected now.
+	ClientStateIdle
+	// ClientStateOnline clients are connected now.
+	ClientStateOnline
+)
+
+// The controller updates last_seen roughly every minute for connected
+// clients, so a client seen within ClientOnlineWithin is treated as
+// connected even when the record has no uptime (e.g. from /rest/user).
+var (
+	ClientOnlineWithin = 5 * time.Minute
+	ClientIdleAfter    = 24 * time.Hour
+)
+
+func (s ClientState) String() string {
+	switch s {
+	case ClientStateOnline:
+		return "online"
+	case ClientStateIdle:
+		return "idle"
+	default:
+		return "offline"
+	}
+}
+
+// IsActive reports whether the client is connected now.
+func (client *Client) IsActive() bool { return client.ConnectionState() == ClientStateOnline }
+
+// ConnectionState classifies the client as of now.
+func (client *Client) ConnectionState() ClientState { return client.ConnectionStateAt(time.Now()) }
+
+// ConnectionStateAt classifies the client as of the given time:
+//   - ClientStateOnline if it has uptime, or was last seen within ClientOnlineWithin;
+//   - ClientStateIdle if it was last seen within ClientIdleAfter;
+//   - ClientStateOffline otherwise, including when it has never been seen.
+func (client *Client) ConnectionStateAt(now time.Time) ClientState {
+	if client.Uptime > 0 {
+		return ClientStateOnline
+	}
+
+	if client.LastSeen == 0 {
+		return ClientStateOffline
+	}
+
+	since := now.Sub(client.LastSeen.Time())
+
+	switch {
+	case since <= ClientOnlineWithin:
+		return ClientStateOnline
+	case since <= ClientIdleAfter:
+		return ClientStateIdle
+	default:
+		return ClientStateOffline
+	}
+}
+
+// SessionDuration returns how long the client has been connected in its
+// current session, from its uptime. ok is false if the record has no
+// uptime, e.g. the client is not connected.
+func (client *Client) SessionDuration() (d time.Duration, ok bool) {
+	if client.Uptime <= 0 {
+		return 0, false
+	}
+
+	return time.Duration(client.Uptime) * time.Second, true
+}
+
+// OfflineDuration returns how long ago the client was last seen. ok is
+// false if it is connected now or has never been seen.
+func (client *Client) OfflineDuration() (d time.Duration, ok bool) {
+	return client.offlineDurationAt(time.Now())
+}
+
+func (client *Client) offlineDurationAt(now time.Time) (time.Duration, bool) {
+	if client.LastSeen == 0 || client.ConnectionStateAt(now) == ClientStateOnline {
+		return 0, false
+	}
+
+	return now.Sub(client.LastSeen.Time()), true
+}
+
+// DisplaySessionDuration returns SessionDuration, e.g. "2h5m", or "-".
+func (client *Client) DisplaySessionDuration() string {
+	if d, ok := client.SessionDuration(); ok {
+		return formatDuration(d)
+	}
+
+	return "-"
+}
+
+// DisplayOfflineDuration returns OfflineDuration, e.g. "2h5m", or "-".
+func (client *Client) DisplayOfflineDuration() string {
+	if d, ok := client.OfflineDuration(); ok {
+		return formatDuration(d)
+	}
+
+	return "-"
+}
+
+// Infrastructure kinds reported by LastSeenBy and ConnectedVia.
+const (
+	SeenByAccessPoint = "ap"
+	SeenBySwitch      = "switch"
+	SeenByGateway     = "gateway"
+)
+
+// LastSeenBy returns the kind of infrastructure device that saw the client
+// most recently, and when. The gateway sees wired and wireless traffic
+// alike, so on a tie the access point or switch wins. kind is empty if no
+// device has seen the client.
+func (client *Client) LastSeenBy() (kind string, ts TimeStamp) {
+	for _, seen := range []struct {
+		kind string
+		ts   TimeStamp
+	}{
+		{SeenByAccessPoint, client.UAPLastSeen},
+		{SeenBySwitch, client.USWLastSeen},
+		{SeenByGateway, client.UGWLastSeen},
+	} {
+		if seen.ts > ts {
+			kind, ts = seen.kind, seen.ts
+		}
+	}
+
+	return kind, ts
+}
+
+// ConnectedVia returns the kind of infrastructure device the client is
+// connected through, based on LastSeenBy and falling back to IsWired.
+func (client *Client) ConnectedVia() string {
+	if kind, _ := client.LastSeenBy(); kind != "" {
+		return kind
+	}
+
+	if client.IsWired {
+		return SeenBySwitch
+	}
+
+	return SeenByAccessPoint
+}
+
+// Thresholds used by Verdict.
+var (
+	WeakSignal      int64 = -75 // dBm
+	LowSatisfaction int64 = 50  // percent
+)
+
+// Verdict summarizes the client's health in a few words: "ok", or the most
+// pressing problem, e.g. "blocked", "offline", or "weak signal (-80 dBm)".
+func (client *Client) Verdict(o DisplayOptions) string {
+	switch state := client.ConnectionState(); {
+	case client.IsBlocked:
+		return "blocked"
+	case state != ClientStateOnline && client.LastSeen == 0:
+		return state.String()
+	case state != ClientStateOnline:
+		return fmt.Sprintf("%s (last seen %s)", state, o.Time(client.LastSeen.Time()))
+	case !client.IsWired && client.Signal != 0 && client.Signal < WeakSignal:
+		return fmt.Sprintf("weak signal (%d dBm)", client.Signal)
+	case client.Satisfaction > 0 && client.Satisfaction < LowSatisfaction:
+		return fmt.Sprintf("poor experience (%d%%)", client.Satisfaction)
+	default:
+		return "ok"
+	}
+}
+
+// DeviceState is the controller's view of a Device's connection.
+type DeviceState int64
+
+// Known UniFi device state codes.
+const (
+	DeviceStateDisconnected     DeviceState = 0
+	DeviceStateConnected        DeviceState = 1
+	DeviceStatePending          DeviceState = 2
+	DeviceStateFirmwareMismatch DeviceState = 3
+	DeviceStateUpgrading        DeviceState = 4
+	DeviceStateProvisioning     DeviceState = 5
+	DeviceStateHeartbeatMissed  DeviceState = 6
+	DeviceStateAdopting         DeviceState = 7
+	DeviceStateDeleting         DeviceState = 8
+	DeviceStateInformError      DeviceState = 9
+	DeviceStateAdoptionFailed   DeviceState = 10
+	DeviceStateIsolated         DeviceState = 11
+)
+
+var deviceStateNames = map[DeviceState]string{
+	DeviceStateDisconnected:     "Disconnected",
+	DeviceStateConnected:        "Connected",
+	DeviceStatePending:          "Pending",
+	DeviceStateFirmwareMismatch: "Firmware Mismatch",
+	DeviceStateUpgrading:        "Upgrading",
+	DeviceStateProvisioning:     "Provisioning",
+	DeviceStateHeartbeatMissed:  "Heartbeat Missed",
+	DeviceStateAdopting:         "Adopting",
+	DeviceStateDeleting:         "Deleting",
+	DeviceStateInformError:      "Inform Error",
+	DeviceStateAdoptionFailed:   "Adoption Failed",
+	DeviceStateIsolated:         "Isolated",
+}
+
+func (s DeviceState) String() string {
+	if name, ok := deviceStateNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Unknown (%d)", int64(s))
+}
+
+// IsOffline reports whether the controller has lost contact with the
+// device: disconnected, or with missed heartbeats.
+func (s DeviceState) IsOffline() bool {
+	return s == DeviceStateDisconnected || s == DeviceStateHeartbeatMissed
+}
+
+// ConnectionState decodes the device's raw State code.
+func (d *Device) ConnectionState() DeviceState { return DeviceState(d.State) }
@@ -0,0 +1,110 @@
+package unifi
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clientsFixtureTime is the time the sample data in
+// testdata/clients_all.json is relative to.
+var clientsFixtureTime = time.Unix(1_700_000_000, 0)
+
+func loadClients(t *testing.T, file string) map[string]Client {
+	t.Helper()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp ClientResponse
+	if err = json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("decoding %s: %v", file, err)
+	}
+
+	clients := map[string]Client{}
+	for _, c := range resp.Data {
+		clients[c.DisplayName()] = c
+	}
+
+	return clients
+}
+
+func TestConnectionStateAt(t *testing.T) {
+	clients := loadClients(t, "testdata/clients_all.json")
+
+	tests := []struct {
+		name    string
+		state   ClientState
+		offline time.Duration // zero when OfflineDuration is not ok
+	}{
+		{"laptop", ClientStateOnline, 0},  // has uptime
+		{"nas", ClientStateOnline, 0},     // no uptime, seen 2m ago
+		{"printer", ClientStateOnline, 0}, // seen exactly ClientOnlineWithin ago
+		{"phone", ClientStateIdle, 301 * time.Second},
+		{"tablet", ClientStateIdle, 3 * time.Hour},
+		{"watch", ClientStateIdle, 24 * time.Hour}, // seen exactly ClientIdleAfter ago
+		{"old-laptop", ClientStateOffline, 24*time.Hour + time.Second},
+		{"never-seen", ClientStateOffline, 0},
+		{"tv", ClientStateOnline, 0}, // uptime wins over a stale last seen
+	}
+
+	if len(clients) != len(tests) {
+		t.Fatalf("got %d clients, want %d", len(clients), len(tests))
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, ok := clients[tc.name]
+			if !ok {
+				t.Fatalf("not in the sample data")
+			}
+
+			if got := c.ConnectionStateAt(clientsFixtureTime); got != tc.state {
+				t.Errorf("got %s, want %s", got, tc.state)
+			}
+
+			d, ok := c.offlineDurationAt(clientsFixtureTime)
+			if ok != (tc.offline != 0) || d != tc.offline {
+				t.Errorf("got offline for %s (%t), want %s", d, ok, tc.offline)
+			}
+		})
+	}
+}
+
+func TestSessionDuration(t *testing.T) {
+	clients := loadClients(t, "testdata/clients_all.json")
+
+	laptop := clients["laptop"]
+	if d, ok := laptop.SessionDuration(); !ok || d != 2*time.Hour+time.Minute {
+		t.Errorf("laptop: got %s (%t), want 2h1m0s", d, ok)
+	}
+
+	nas := clients["nas"]
+	if d, ok := nas.SessionDuration(); ok {
+		t.Errorf("nas: got %s, want none without uptime", d)
+	}
+}
+
+func TestClientDisplaySession(t *testing.T) {
+	clients := loadClients(t, "testdata/clients_all.json")
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"laptop", " connected for 2h1m "},
+		{"old-laptop", " offline for "},
+	}
+
+	for _, tc := range tests {
+		c := clients[tc.name]
+
+		if got := c.Display(DisplayOptions{}); !strings.Contains(got, tc.want) {
+			t.Errorf("%s: got %q, want it to contain %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package unifi
+
+import "time"
+
+// SessionStats counts the HTTP requests made by a Session.
+type SessionStats struct {
+	Requests          int64     `json:"requests"`
+	Failures          int64     `json:"failures"`
+	Reauthentications int64     `json:"reauthentications"`
+	LastError         string    `json:"last_error,omitempty"`
+	LastErrorAt       time.Time `json:"last_error_at,omitempty"`
+}
+
+// Stats returns the request counts of the session so far. Like the rest
+// of Session it is not safe to call concurrently with other methods.
+func (s *Session) Stats() SessionStats { return s.stats }
+
+func (s *Session) recordRequest(err error) {
+	s.stats.Requests++
+
+	if err != nil {
+		s.stats.Failures++
+		s.stats.LastError = err.Error()
+		s.stats.LastErrorAt = time.Now()
+	}
+}
+
+// CacheStats counts the lookups served from a cache, and those that had to
+// fetch.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
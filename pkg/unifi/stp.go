@@ -0,0 +1,106 @@
+package unifi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// STPPort is the spanning tree state of one switch port.
+type STPPort struct {
+	Device      string     `json:"device"`
+	MAC         MAC        `json:"mac"`
+	Port        int64      `json:"port"`
+	PortName    string     `json:"port_name,omitempty"`
+	State       string     `json:"state"`
+	PathCost    int64      `json:"path_cost,omitempty"`
+	IsUp        bool       `json:"up"`
+	Unexpected  bool       `json:"unexpected"`
+	LastBlocked *time.Time `json:"last_blocked,omitempty"` // nil if no recent event blocked the port
+}
+
+// IsBlocking reports whether STP is discarding traffic on the port.
+func (p STPPort) IsBlocking() bool {
+	switch strings.ToLower(p.State) {
+	case "blocking", "discarding":
+		return true
+	default:
+		return false
+	}
+}
+
+// STPStatus is the spanning tree state of every switch port, and the recent
+// STP port blocking events, oldest first.
+type STPStatus struct {
+	Ports  []STPPort `json:"ports"`
+	Events []Event   `json:"events,omitempty"`
+}
+
+// GetSTPStatus returns the spanning tree state of the switch ports,
+// correlated with the recent STP port blocking events.
+func (s *Session) GetSTPStatus() (STPStatus, error) {
+	devices, err := s.GetDevices()
+	if err != nil {
+		return STPStatus{}, err
+	}
+
+	events, err := s.GetRecentEvents()
+	if err != nil {
+		return STPStatus{}, err
+	}
+
+	return BuildSTPStatus(devices, events), nil
+}
+
+// BuildSTPStatus reports the ports of devices that have an STP state. A
+// blocking port with its link up means there is a second path between
+// switches; STP is keeping a loop from forming, but unless the redundant
+// link is deliberate it is flagged as unexpected.
+func BuildSTPStatus(devices []Device, events []Event) STPStatus {
+	var status STPStatus
+
+	lastBlocked := map[string]time.Time{}
+
+	for _, e := range events {
+		if e.Key != EventTypeSwitchSTPPortBlocking {
+			continue
+		}
+
+		status.Events = append(status.Events, e)
+
+		key := stpPortKey(e.Switch, e.Port)
+		if e.DateTime.After(lastBlocked[key]) {
+			lastBlocked[key] = e.DateTime
+		}
+	}
+
+	for _, d := range devices {
+		for _, p := range d.PortTable {
+			if p.STPState == "" {
+				continue
+			}
+
+			port := STPPort{
+				Device:   firstNonEmpty(d.Name, d.MAC.String()),
+				MAC:      d.MAC,
+				Port:     p.PortIndex,
+				PortName: p.Name,
+				State:    p.STPState,
+				PathCost: p.STPPathCost,
+				IsUp:     p.IsUp,
+			}
+
+			if t, ok := lastBlocked[stpPortKey(d.MAC, p.PortIndex)]; ok {
+				port.LastBlocked = &t
+			}
+
+			port.Unexpected = port.IsBlocking() && port.IsUp
+
+			status.Ports = append(status.Ports, port)
+		}
+	}
+
+	return status
+}
+
+func stpPortKey(mac MAC, port int64) string { return fmt.Sprintf("%s/%d", normalMAC(mac), port) }
@@ -0,0 +1,42 @@
+package unifi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBuildSTPStatusLastBlocked(t *testing.T) {
+	devices := loadDevices(t, "testdata/device_usw.json")
+	blocked := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	events := []Event{{Key: EventTypeSwitchSTPPortBlocking, Switch: devices[0].MAC, Port: 4, DateTime: blocked}}
+
+	status := BuildSTPStatus(devices, events)
+	if len(status.Ports) == 0 {
+		t.Fatal("no STP ports in the sample data")
+	}
+
+	for _, p := range status.Ports {
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var obj map[string]any
+		if err = json.Unmarshal(data, &obj); err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := obj["last_blocked"]
+
+		switch {
+		case p.Port == 4 && (p.LastBlocked == nil || !p.LastBlocked.Equal(blocked)):
+			t.Errorf("port 4: got last blocked %v, want %v", p.LastBlocked, blocked)
+		case p.Port == 4 && !ok:
+			t.Errorf("port 4: last_blocked missing from %s", data)
+		case p.Port != 4 && ok:
+			t.Errorf("port %d: never blocked, but got %s", p.Port, data)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package unifi
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Each request, including reading the response, must finish within
+// requestTimeout; the phase timeouts below can only make it fail sooner.
+const requestTimeout = time.Minute
+
+// WithDialTimeout limits how long connecting to the controller may take.
+// Zero keeps the default of the HTTP transport.
+func WithDialTimeout(d time.Duration) Option { return func(s *Session) { s.dialTimeout = d } }
+
+// WithTLSHandshakeTimeout limits how long the TLS handshake may take. Zero
+// keeps the default of the HTTP transport (10s).
+func WithTLSHandshakeTimeout(d time.Duration) Option {
+	return func(s *Session) { s.tlsHandshakeTimeout = d }
+}
+
+// WithResponseHeaderTimeout limits how long the controller may take to
+// start responding once a request is sent. Zero means no limit other than
+// the overall one of a minute per request.
+func WithResponseHeaderTimeout(d time.Duration) Option {
+	return func(s *Session) { s.responseHeaderTimeout = d }
+}
+
+// transport returns base, or a copy of it with the configured timeouts if
+// any are set and base is an *http.Transport.
+func (s *Session) transport(base http.RoundTripper) http.RoundTripper {
+	if s.dialTimeout <= 0 && s.tlsHandshakeTimeout <= 0 && s.responseHeaderTimeout <= 0 {
+		return base
+	}
+
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return base
+	}
+
+	t = t.Clone()
+
+	if s.dialTimeout > 0 {
+		t.DialContext = (&net.Dialer{Timeout: s.dialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	}
+
+	if s.tlsHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = s.tlsHandshakeTimeout
+	}
+
+	if s.responseHeaderTimeout > 0 {
+		t.ResponseHeaderTimeout = s.responseHeaderTimeout
+	}
+
+	return t
+}
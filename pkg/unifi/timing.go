@@ -0,0 +1,115 @@
+package unifi
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestTiming breaks down how long one controller request took. The DNS,
+// Connect, and TLS phases are zero when a kept-alive connection is reused.
+// TTFB is from sending the request to the first response byte, and Total
+// includes reading the response body.
+type RequestTiming struct {
+	Method  string        `json:"method"`
+	Path    string        `json:"path"`
+	Reused  bool          `json:"reused"`
+	DNS     time.Duration `json:"dns"`
+	Connect time.Duration `json:"connect"`
+	TLS     time.Duration `json:"tls"`
+	TTFB    time.Duration `json:"ttfb"`
+	Total   time.Duration `json:"total"`
+}
+
+func (t RequestTiming) String() string {
+	conn := fmt.Sprintf("dns=%s connect=%s tls=%s", round(t.DNS), round(t.Connect), round(t.TLS))
+	if t.Reused {
+		conn = "reused connection"
+	}
+
+	return fmt.Sprintf("%s %s: %s ttfb=%s total=%s", t.Method, t.Path, conn, round(t.TTFB), round(t.Total))
+}
+
+func round(d time.Duration) time.Duration { return d.Round(100 * time.Microsecond) }
+
+// WithTiming writes a RequestTiming line to w for each controller request,
+// including each of the requests made by multi-request operations such as
+// GetNames.
+func WithTiming(w io.Writer) Option { return func(s *Session) { s.timingWriter = w } }
+
+// timeRequest returns ctx traced for the request timing, and a func that
+// writes the timing once the response has been read. Nothing is traced
+// without WithTiming.
+//
+// The trace callbacks can run concurrently, as dual-stack dials race each
+// other, and a losing dial can finish after the request, so t is guarded by
+// mu, only the connection that was used is timed, and nothing is recorded
+// once the timing has been written.
+func (s *Session) timeRequest(ctx context.Context, method string, u fmt.Stringer) (context.Context, func()) {
+	if s.timingWriter == nil {
+		return ctx, func() {}
+	}
+
+	var (
+		mu    sync.Mutex
+		t     = RequestTiming{Method: method, Path: strings.TrimPrefix(u.String(), s.Endpoint)}
+		start = time.Now()
+		done  bool
+
+		connStarts = map[string]time.Time{}
+		connects   = map[string]time.Duration{}
+
+		dnsStart, tlsStart, wrote time.Time
+	)
+
+	record := func(fn func()) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !done {
+			fn()
+		}
+	}
+
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { record(func() { dnsStart = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { record(func() { t.DNS = time.Since(dnsStart) }) },
+		ConnectStart: func(_, addr string) {
+			record(func() { connStarts[addr] = time.Now() })
+		},
+		ConnectDone: func(_, addr string, err error) {
+			record(func() {
+				if err == nil {
+					connects[addr] = time.Since(connStarts[addr])
+				}
+			})
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			record(func() {
+				t.Reused = info.Reused
+				if info.Conn != nil {
+					t.Connect = connects[info.Conn.RemoteAddr().String()]
+				}
+			})
+		},
+		TLSHandshakeStart:    func() { record(func() { tlsStart = time.Now() }) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { record(func() { t.TLS = time.Since(tlsStart) }) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { record(func() { wrote = time.Now() }) },
+		GotFirstResponseByte: func() { record(func() { t.TTFB = time.Since(wrote) }) },
+	})
+
+	return ctx, func() {
+		mu.Lock()
+		done = true
+		t.Total = time.Since(start)
+		timing := t
+		mu.Unlock()
+
+		fmt.Fprintf(s.timingWriter, "timing: %s\n", timing)
+	}
+}
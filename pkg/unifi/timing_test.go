@@ -0,0 +1,87 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// remoteConn is a net.Conn that only knows its remote address.
+type remoteConn struct {
+	net.Conn
+
+	addr net.Addr
+}
+
+func (c remoteConn) RemoteAddr() net.Addr { return c.addr }
+
+func TestTimeRequestDualStackDial(t *testing.T) {
+	var out bytes.Buffer
+
+	s := &Session{Endpoint: "https://controller", timingWriter: &out}
+	u, _ := url.Parse("https://controller/api/self")
+
+	ctx, timed := s.timeRequest(context.Background(), "GET", u)
+	trace := httptrace.ContextClientTrace(ctx)
+
+	const v4, v6 = "192.0.2.1:443", "[2001:db8::1]:443"
+
+	// Both dials race; the IPv6 one wins after about 20ms, the IPv4 one
+	// fails.
+	var wg sync.WaitGroup
+
+	for _, addr := range []string{v4, v6} {
+		wg.Add(1)
+
+		go func(addr string) {
+			defer wg.Done()
+
+			trace.ConnectStart("tcp", addr)
+
+			if addr == v4 {
+				trace.ConnectDone("tcp", addr, errors.New("refused"))
+
+				return
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			trace.ConnectDone("tcp", addr, nil)
+		}(addr)
+	}
+
+	wg.Wait()
+
+	winner, _ := net.ResolveTCPAddr("tcp", v6)
+	trace.GotConn(httptrace.GotConnInfo{Conn: remoteConn{addr: winner}})
+	trace.GotFirstResponseByte()
+
+	timed()
+
+	// A dial that lost the race may still report after the request.
+	trace.ConnectStart("tcp", v4)
+	trace.ConnectDone("tcp", v4, nil)
+
+	line := out.String()
+	if !strings.HasPrefix(line, "timing: GET /api/self: ") {
+		t.Fatalf("unexpected timing line %q", line)
+	}
+
+	var connect time.Duration
+
+	for _, field := range strings.Fields(line) {
+		if v, ok := strings.CutPrefix(field, "connect="); ok {
+			connect, _ = time.ParseDuration(v)
+		}
+	}
+
+	if connect < 20*time.Millisecond {
+		t.Errorf("got connect=%s, want the winning dial's time of at least 20ms", connect)
+	}
+}
@@ -0,0 +1,283 @@
+package unifi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Topology node kinds.
+const (
+	TopologySite   = "site"
+	TopologyDevice = "device"
+	TopologyClient = "client"
+)
+
+// TopologyNode is the site, a device, or a client in the physical network
+// topology. Port is the port on the parent node this node is connected to,
+// when known.
+type TopologyNode struct {
+	Kind     string          `json:"kind"`
+	Name     string          `json:"name"`
+	MAC      MAC             `json:"mac,omitempty"`
+	Type     string          `json:"type,omitempty"`
+	Model    string          `json:"model,omitempty"`
+	State    string          `json:"state,omitempty"`
+	Port     int64           `json:"port,omitempty"`
+	Children []*TopologyNode `json:"children,omitempty"`
+}
+
+func (n *TopologyNode) String() string {
+	var parts []string
+
+	if n.Port > 0 {
+		parts = append(parts, fmt.Sprintf("[port %d]", n.Port))
+	}
+
+	parts = append(parts, n.Name)
+
+	if n.Kind == TopologySite {
+		return strings.Join(parts, " ")
+	}
+
+	if kind := nonEmpty(n.Type, n.Model); len(kind) > 0 {
+		parts = append(parts, fmt.Sprintf("(%s)", strings.Join(kind, " ")))
+	}
+
+	parts = append(parts, n.MAC.String())
+
+	if n.State != "" {
+		parts = append(parts, n.State)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Find returns the node with the given MAC, or failing that the given
+// name, anywhere in the tree rooted at n.
+func (n *TopologyNode) Find(key string) *TopologyNode {
+	var byMAC, byName *TopologyNode
+
+	n.Walk(func(node *TopologyNode, _ int) {
+		if byMAC == nil && node.MAC != "" && strings.EqualFold(string(node.MAC), key) {
+			byMAC = node
+		}
+
+		if byName == nil && strings.EqualFold(node.Name, key) {
+			byName = node
+		}
+	})
+
+	if byMAC != nil {
+		return byMAC
+	}
+
+	return byName
+}
+
+// Walk calls fn for n and every node below it, depth first, with the depth
+// relative to n.
+func (n *TopologyNode) Walk(fn func(node *TopologyNode, depth int)) {
+	n.walk(fn, 0)
+}
+
+func (n *TopologyNode) walk(fn func(*TopologyNode, int), depth int) {
+	fn(n, depth)
+
+	for _, child := range n.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// Path returns the nodes from n down to the node with the given MAC,
+// inclusive, or nil if it is not below n.
+func (n *TopologyNode) Path(mac MAC) []*TopologyNode {
+	if n.MAC != "" && normalMAC(n.MAC) == normalMAC(mac) {
+		return []*TopologyNode{n}
+	}
+
+	for _, child := range n.Children {
+		if path := child.Path(mac); path != nil {
+			return append([]*TopologyNode{n}, path...)
+		}
+	}
+
+	return nil
+}
+
+// GetTopology returns the site topology: the devices arranged by their
+// uplinks (gateway, then switches, then access points) with the connected
+// clients below the device they are attached to. Devices whose uplink is
+// unknown are listed directly below the site.
+func (s *Session) GetTopology() (*TopologyNode, error) {
+	devices, err := s.GetDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.GetClients()
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildTopology(s.site, devices, clients), nil
+}
+
+// BuildTopology arranges devices and clients into a tree below a site node
+// named site. A device's parent is found from its uplink, its last known
+// uplink, or the downlink table of another device, in that order.
+func BuildTopology(site string, devices []Device, clients []Client) *TopologyNode {
+	if site == "" {
+		site = "default"
+	}
+
+	root := &TopologyNode{Kind: TopologySite, Name: site}
+	nodes := map[MAC]*TopologyNode{}
+
+	for _, d := range devices {
+		nodes[normalMAC(d.MAC)] = &TopologyNode{
+			Kind:  TopologyDevice,
+			Name:  firstNonEmpty(d.Name, d.MAC.String()),
+			MAC:   d.MAC,
+			Type:  d.DeviceType,
+			Model: d.Model,
+			State: d.ConnectionState().String(),
+		}
+	}
+
+	// Downlink tables, keyed by the downstream device.
+	downlinks := map[MAC]Downlink{}
+	downlinkParents := map[MAC]MAC{}
+
+	for _, d := range devices {
+		for _, link := range d.DownlinkTable {
+			downlinks[normalMAC(link.MAC)] = link
+			downlinkParents[normalMAC(link.MAC)] = normalMAC(d.MAC)
+		}
+	}
+
+	parents := map[MAC]MAC{}
+
+	for _, d := range devices {
+		mac := normalMAC(d.MAC)
+		parent, port := deviceUplink(d)
+
+		if parent == "" {
+			parent = downlinkParents[mac]
+			port = downlinks[mac].PortIndex
+		}
+
+		if _, ok := nodes[parent]; !ok || parent == mac {
+			continue
+		}
+
+		parents[mac] = parent
+		nodes[mac].Port = port
+	}
+
+	for _, d := range devices {
+		mac := normalMAC(d.MAC)
+
+		parent, ok := parents[mac]
+		if !ok || createsCycle(parents, mac) {
+			nodes[mac].Port = 0
+			root.Children = append(root.Children, nodes[mac])
+
+			continue
+		}
+
+		nodes[parent].Children = append(nodes[parent].Children, nodes[mac])
+	}
+
+	for ix := range clients {
+		c := &clients[ix]
+		node := &TopologyNode{
+			Kind: TopologyClient,
+			Name: c.DisplayName(),
+			MAC:  c.MAC,
+			Type: "wireless",
+		}
+
+		if c.IsWired {
+			node.Type = "wired"
+			node.Port = c.SwitchPort
+		} else if c.ESSID != "" {
+			node.Model = c.ESSID
+		}
+
+		if parent, ok := nodes[normalMAC(MAC(c.UpstreamMAC()))]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			root.Children = append(root.Children, node)
+		}
+	}
+
+	root.Walk(func(node *TopologyNode, _ int) { sortTopology(node.Children) })
+
+	return root
+}
+
+// deviceUplink returns the MAC of the device d is connected to and the port
+// on that device, preferring the current uplink over the last known one.
+func deviceUplink(d Device) (MAC, int64) {
+	if d.Uplink.UplinkMAC != "" {
+		return normalMAC(d.Uplink.UplinkMAC), d.Uplink.UplinkRemotePort
+	}
+
+	if d.LastUplink.UplinkMAC != "" {
+		return normalMAC(d.LastUplink.UplinkMAC), d.LastUplink.UplinkRemotePort
+	}
+
+	return "", 0
+}
+
+// createsCycle reports whether following parents from mac leads back to
+// mac itself.
+func createsCycle(parents map[MAC]MAC, mac MAC) bool {
+	seen := map[MAC]bool{mac: true}
+
+	for cur, ok := parents[mac]; ok; cur, ok = parents[cur] {
+		if cur == mac {
+			return true
+		}
+
+		if seen[cur] {
+			return false
+		}
+
+		seen[cur] = true
+	}
+
+	return false
+}
+
+// sortTopology orders devices before clients, then by port and name.
+func sortTopology(nodes []*TopologyNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		lhs, rhs := nodes[i], nodes[j]
+
+		if lhs.Kind != rhs.Kind {
+			return lhs.Kind == TopologyDevice
+		}
+
+		if lhs.Port != rhs.Port {
+			return lhs.Port < rhs.Port
+		}
+
+		return strings.ToLower(lhs.Name) < strings.ToLower(rhs.Name)
+	})
+}
+
+func normalMAC(mac MAC) MAC { return MAC(strings.ToLower(string(mac))) }
+
+func nonEmpty(s ...string) []string {
+	var out []string
+
+	for _, candidate := range s {
+		if len(candidate) > 0 {
+			out = append(out, candidate)
+		}
+	}
+
+	return out
+}
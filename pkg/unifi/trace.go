@@ -0,0 +1,62 @@
+package unifi
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/johnweldon/unifi-scheduler/pkg/unifi"
+
+// WithTracerProvider records a span for each high level operation (such as
+// GetClients), with a child span for each HTTP request it makes.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(s *Session) { s.tracer = tp.Tracer(tracerName) }
+}
+
+// startSpan starts a span that becomes the parent of spans started until
+// the returned func is called. The returned func ends the span, recording
+// *errp if it is not nil.
+func (s *Session) startSpan(name string, attrs ...attribute.KeyValue) (context.Context, func(errp *error)) {
+	if s.tracer == nil {
+		s.tracer = noop.NewTracerProvider().Tracer(tracerName)
+	}
+
+	parent := s.traceCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, span := s.tracer.Start(parent, name, trace.WithAttributes(attrs...))
+	s.traceCtx = ctx
+
+	return ctx, func(errp *error) {
+		if errp != nil && *errp != nil {
+			span.RecordError(*errp)
+			span.SetStatus(codes.Error, (*errp).Error())
+		}
+
+		span.End()
+
+		s.traceCtx = parent
+	}
+}
+
+func clientsSpanName(all bool) string {
+	if all {
+		return "GetAllClients"
+	}
+
+	return "GetClients"
+}
+
+func eventsSpanName(all bool) string {
+	if all {
+		return "GetAllEvents"
+	}
+
+	return "GetRecentEvents"
+}
@@ -0,0 +1,83 @@
+package unifi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SetClientNote sets the note on the user record of the client with the
+// given MAC, or clears it if note is empty. Other fields of the record are
+// preserved.
+func (s *Session) SetClientNote(mac MAC, note string) (string, error) {
+	return s.updateUser(mac, func(user map[string]any) {
+		user["note"] = note
+		user["noted"] = note != ""
+	})
+}
+
+// RenameClient sets the name on the user record of the client with the
+// given MAC and, if fixedIP is not empty, assigns it as the client's fixed
+// IP. Other fields of the record, including any existing fixed IP when
+// fixedIP is empty, are preserved.
+func (s *Session) RenameClient(mac MAC, name, fixedIP string) (string, error) {
+	return s.updateUser(mac, func(user map[string]any) {
+		user["name"] = name
+
+		if fixedIP != "" {
+			user["fixed_ip"] = fixedIP
+			user["use_fixedip"] = true
+		}
+	})
+}
+
+// updateUser applies update to the generic JSON representation of the user
+// record of the client with the given MAC, and writes the whole record
+// back, so that fields not modeled by Client survive the read-modify-write.
+func (s *Session) updateUser(mac MAC, update func(map[string]any)) (string, error) {
+	user, err := s.getRawUser(mac)
+	if err != nil {
+		return "", err
+	}
+
+	id, _ := user["_id"].(string)
+	if len(id) == 0 {
+		return "", fmt.Errorf("user %s has no id", mac)
+	}
+
+	update(user)
+
+	payload, err := json.Marshal(user)
+	if err != nil {
+		return "", fmt.Errorf("marshalling user %s: %w", mac, err)
+	}
+
+	return s.action(http.MethodPut, "/rest/user/"+id, bytes.NewBuffer(payload))
+}
+
+// getRawUser returns the generic JSON representation of the user record of
+// the client with the given MAC.
+func (s *Session) getRawUser(mac MAC) (map[string]any, error) {
+	var (
+		data string
+		resp struct {
+			Data []map[string]any `json:"data"`
+		}
+		err error
+	)
+
+	if data, err = s.GetUserByMAC(mac.String()); err != nil {
+		return nil, fmt.Errorf("fetching user %s: %w", mac, err)
+	}
+
+	if err = json.Unmarshal([]byte(data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshalling user %s: %w", mac, err)
+	}
+
+	if len(resp.Data) < 1 {
+		return nil, fmt.Errorf("zero results: %s", data)
+	}
+
+	return resp.Data[0], nil
+}
@@ -0,0 +1,64 @@
+package unifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// UserGroup describes a user group, which caps the bandwidth of its
+// clients. Rates are in Kbps; -1 means unlimited.
+type UserGroup struct {
+	ID string `json:"_id,omitempty"`
+
+	Name           string `json:"name,omitempty"`
+	QOSRateMaxDown int64  `json:"qos_rate_max_down,omitempty"`
+	QOSRateMaxUp   int64  `json:"qos_rate_max_up,omitempty"`
+	SiteID         string `json:"site_id,omitempty"`
+}
+
+func (g *UserGroup) String() string {
+	return fmt.Sprintf("%-24s %-25s %11s↓ %11s↑", g.ID, g.Name, displayQOSRate(g.QOSRateMaxDown), displayQOSRate(g.QOSRateMaxUp))
+}
+
+func displayQOSRate(kbps int64) string {
+	if kbps <= 0 {
+		return "unlimited"
+	}
+
+	return formatRate(kbps * 1000)
+}
+
+// ListUserGroups describes the configured user groups.
+func (s *Session) ListUserGroups() (string, error) {
+	return s.action(http.MethodGet, "/rest/usergroup", nil)
+}
+
+// GetUserGroups returns the configured user groups.
+func (s *Session) GetUserGroups() ([]UserGroup, error) {
+	var (
+		data string
+		resp UserGroupResponse
+		err  error
+	)
+
+	if data, err = s.ListUserGroups(); err != nil {
+		return nil, fmt.Errorf("fetching user groups: %w", err)
+	}
+
+	if err = json.Unmarshal([]byte(data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshalling user groups: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// SetClientUserGroup moves the client with the given MAC to the user group
+// groupID. Other fields of the user record are preserved.
+func (s *Session) SetClientUserGroup(mac MAC, groupID string) (string, error) {
+	if len(groupID) == 0 {
+		return "", fmt.Errorf("missing user group id")
+	}
+
+	return s.updateUser(mac, func(user map[string]any) { user["usergroup_id"] = groupID })
+}
@@ -0,0 +1,97 @@
+package unifi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WLAN describes a wireless network (SSID) configuration.
+type WLAN struct {
+	ID string `json:"_id,omitempty"`
+
+	Enabled       bool   `json:"enabled"`
+	IsGuest       bool   `json:"is_guest,omitempty"`
+	Name          string `json:"name,omitempty"`
+	NetworkConfID string `json:"networkconf_id,omitempty"`
+	Security      string `json:"security,omitempty"`
+	SiteID        string `json:"site_id,omitempty"`
+	UsergroupID   string `json:"usergroup_id,omitempty"`
+	WPAMode       string `json:"wpa_mode,omitempty"`
+}
+
+func (w *WLAN) String() string {
+	enabled := "disabled"
+	if w.Enabled {
+		enabled = "enabled"
+	}
+
+	guest := ""
+	if w.IsGuest {
+		guest = "guest"
+	}
+
+	return fmt.Sprintf("%-24s %-25s %-8s %-8s %s", w.ID, w.Name, enabled, w.Security, guest)
+}
+
+// ListWLANs describes the configured wireless networks.
+func (s *Session) ListWLANs() (string, error) { return s.action(http.MethodGet, "/rest/wlanconf", nil) }
+
+// GetWLANs returns the configured wireless networks.
+func (s *Session) GetWLANs() ([]WLAN, error) {
+	var (
+		data string
+		resp WLANResponse
+		err  error
+	)
+
+	if data, err = s.ListWLANs(); err != nil {
+		return nil, fmt.Errorf("fetching wlans: %w", err)
+	}
+
+	if err = json.Unmarshal([]byte(data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshalling wlans: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// SetWLANEnabled enables or disables the wireless network with the given
+// id. The full existing configuration is read and written back with only
+// the enabled flag changed, so that secrets and other settings are kept.
+func (s *Session) SetWLANEnabled(id string, enabled bool) (string, error) {
+	if len(id) == 0 {
+		return "", fmt.Errorf("missing wlan id")
+	}
+
+	var (
+		data string
+		resp struct {
+			Data []map[string]any `json:"data"`
+		}
+		err error
+	)
+
+	if data, err = s.action(http.MethodGet, "/rest/wlanconf/"+id, nil); err != nil {
+		return "", fmt.Errorf("fetching wlan %q: %w", id, err)
+	}
+
+	if err = json.Unmarshal([]byte(data), &resp); err != nil {
+		return "", fmt.Errorf("unmarshalling wlan %q: %w", id, err)
+	}
+
+	if len(resp.Data) < 1 {
+		return "", fmt.Errorf("zero results: %s", data)
+	}
+
+	wlan := resp.Data[0]
+	wlan["enabled"] = enabled
+
+	payload, err := json.Marshal(wlan)
+	if err != nil {
+		return "", fmt.Errorf("marshalling wlan %q: %w", id, err)
+	}
+
+	return s.action(http.MethodPut, "/rest/wlanconf/"+id, bytes.NewBuffer(payload))
+}